@@ -0,0 +1,367 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Bind group validation errors.
+//
+// BindGroupDescriptor.Validate wraps these in a *BindGroupError that
+// records the offending binding; test for them with errors.Is.
+var (
+	// ErrBindingMissing means a layout binding has no entry.
+	ErrBindingMissing = errors.New("layout binding not provided")
+	// ErrBindingNotInLayout means an entry's binding number is not in the layout.
+	ErrBindingNotInLayout = errors.New("binding not in layout")
+	// ErrBindingResource means an entry's resource has the wrong kind or an
+	// unknown handle.
+	ErrBindingResource = errors.New("invalid binding resource")
+	// ErrResourceUsage means a buffer or texture lacks the usage its binding needs.
+	ErrResourceUsage = errors.New("resource lacks binding usage")
+	// ErrBufferBindingSize means a buffer range is out of bounds, smaller than
+	// MinBindingSize, or larger than the binding size limit.
+	ErrBufferBindingSize = errors.New("invalid buffer binding size")
+	// ErrBufferOffsetAlignment means a buffer offset is not a multiple of
+	// MinUniformBufferOffsetAlignment or MinStorageBufferOffsetAlignment.
+	ErrBufferOffsetAlignment = errors.New("misaligned buffer binding offset")
+	// ErrTextureViewMismatch means a texture view's sample type, format,
+	// dimension or sample count does not match its binding.
+	ErrTextureViewMismatch = errors.New("texture view does not match binding")
+	// ErrSamplerType means a sampler does not match its binding type.
+	ErrSamplerType = errors.New("sampler does not match binding type")
+	// ErrBindingArrayLength means a binding array resource is empty, longer
+	// than the layout's Count, or shorter without
+	// FeaturePartiallyBoundBindingArrays.
+	ErrBindingArrayLength = errors.New("invalid binding array length")
+)
+
+// TextureViewInfo describes the texture view behind a binding handle, as
+// returned by BindGroupLookup.TextureView.
+type TextureViewInfo struct {
+	// Format is the view format.
+	Format TextureFormat
+	// Dimension is the view dimension (Undefined is read as 2D).
+	Dimension TextureViewDimension
+	// Aspect is the view aspect (Undefined is read as All).
+	Aspect TextureAspect
+	// SampleCount is the texture sample count (0 is read as 1).
+	SampleCount uint32
+	// Usage is the usage of the viewed texture.
+	Usage TextureUsage
+}
+
+// BindGroupLookup resolves implementation-specific handles to resource
+// descriptions for BindGroupDescriptor.Validate. Each function reports
+// false for unknown handles; a nil function knows no handles.
+type BindGroupLookup struct {
+	// Buffer describes a buffer handle.
+	Buffer func(buffer uintptr) (BufferDescriptor, bool)
+	// TextureView describes a texture view handle.
+	TextureView func(view uintptr) (TextureViewInfo, bool)
+	// Sampler describes a sampler handle.
+	Sampler func(sampler uintptr) (SamplerDescriptor, bool)
+}
+
+// BindGroupError reports an invalid bind group entry or a missing binding.
+type BindGroupError struct {
+	// Binding is the binding number.
+	Binding uint32
+	// Err describes the problem and wraps one of the Err* sentinels.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BindGroupError) Error() string {
+	return fmt.Sprintf("gputypes: bind group binding %d: %v", e.Binding, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BindGroupError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that the entries match layout, using lookup to describe
+// the bound resources, and returns the first violation as a *BindGroupError.
+//
+// Every layout binding must be provided exactly once, with a resource of
+// the right kind:
+//   - buffers need Uniform or Storage usage, an in-bounds range of at least
+//     MinBindingSize and at most MaxUniformBufferBindingSize or
+//     MaxStorageBufferBindingSize, and an offset aligned to
+//     MinUniformBufferOffsetAlignment or MinStorageBufferOffsetAlignment
+//   - samplers must be comparison samplers exactly for Comparison
+//     bindings, and use only Nearest filtering for NonFiltering bindings
+//   - texture views need TextureBinding usage, a sample type the binding
+//     accepts (a Float binding needs a filterable view), and matching view
+//     dimension and multisampling
+//   - storage texture views need StorageBinding usage, the binding's format
+//     and dimension, and a single sample
+//
+// Binding arrays (layout entries with Count > 0) take the matching array
+// resource, each element checked as above. It must have Count elements, or
+// between 1 and Count with FeaturePartiallyBoundBindingArrays.
+//
+// The Layout handle of d is not used; the layout is passed explicitly.
+func (d *BindGroupDescriptor) Validate(layout *BindGroupLayoutDescriptor, limits Limits, features Features, lookup BindGroupLookup) error {
+	byBinding := make(map[uint32]*BindGroupLayoutEntry, len(layout.Entries))
+	for i := range layout.Entries {
+		byBinding[layout.Entries[i].Binding] = &layout.Entries[i]
+	}
+
+	seen := make(map[uint32]bool, len(d.Entries))
+	for _, e := range d.Entries {
+		entryErr := func(err error) error {
+			return &BindGroupError{Binding: e.Binding, Err: err}
+		}
+		le, ok := byBinding[e.Binding]
+		if !ok {
+			return entryErr(ErrBindingNotInLayout)
+		}
+		if seen[e.Binding] {
+			return entryErr(ErrDuplicateBinding)
+		}
+		seen[e.Binding] = true
+		if err := le.validateResource(e.Resource, limits, features, lookup); err != nil {
+			return entryErr(err)
+		}
+	}
+
+	for _, le := range layout.Entries {
+		if !seen[le.Binding] {
+			return &BindGroupError{Binding: le.Binding, Err: ErrBindingMissing}
+		}
+	}
+	return nil
+}
+
+// validateResource checks a bound resource against the layout entry.
+func (le *BindGroupLayoutEntry) validateResource(r BindingResource, limits Limits, features Features, lookup BindGroupLookup) error {
+	if le.Count > 0 {
+		return le.validateArray(r, limits, features, lookup)
+	}
+	switch {
+	case le.Buffer != nil:
+		b, ok := r.(BufferBinding)
+		if !ok {
+			return fmt.Errorf("%w: %T for buffer binding", ErrBindingResource, r)
+		}
+		return le.Buffer.validateBinding(b, limits, lookup)
+	case le.Sampler != nil:
+		s, ok := r.(SamplerBinding)
+		if !ok {
+			return fmt.Errorf("%w: %T for sampler binding", ErrBindingResource, r)
+		}
+		return le.Sampler.validateBinding(s, lookup)
+	case le.Texture != nil:
+		v, ok := r.(TextureViewBinding)
+		if !ok {
+			return fmt.Errorf("%w: %T for texture binding", ErrBindingResource, r)
+		}
+		return le.Texture.validateBinding(v, features, lookup)
+	case le.StorageTexture != nil:
+		v, ok := r.(TextureViewBinding)
+		if !ok {
+			return fmt.Errorf("%w: %T for storage texture binding", ErrBindingResource, r)
+		}
+		return le.StorageTexture.validateBinding(v, lookup)
+	default:
+		return ErrBindingKind
+	}
+}
+
+// validateArray checks a bound array resource against a binding array
+// entry, element by element.
+func (le *BindGroupLayoutEntry) validateArray(r BindingResource, limits Limits, features Features, lookup BindGroupLookup) error {
+	var (
+		n    int
+		elem func(i int) error
+	)
+	switch a := r.(type) {
+	case BufferArrayBinding:
+		if le.Buffer == nil {
+			break
+		}
+		n = len(a.Buffers)
+		elem = func(i int) error { return le.Buffer.validateBinding(a.Buffers[i], limits, lookup) }
+	case SamplerArrayBinding:
+		if le.Sampler == nil {
+			break
+		}
+		n = len(a.Samplers)
+		elem = func(i int) error { return le.Sampler.validateBinding(SamplerBinding{Sampler: a.Samplers[i]}, lookup) }
+	case TextureViewArrayBinding:
+		switch {
+		case le.Texture != nil:
+			elem = func(i int) error {
+				return le.Texture.validateBinding(TextureViewBinding{TextureView: a.TextureViews[i]}, features, lookup)
+			}
+		case le.StorageTexture != nil:
+			elem = func(i int) error {
+				return le.StorageTexture.validateBinding(TextureViewBinding{TextureView: a.TextureViews[i]}, lookup)
+			}
+		}
+		n = len(a.TextureViews)
+	}
+	if elem == nil {
+		return fmt.Errorf("%w: %T for binding array", ErrBindingResource, r)
+	}
+
+	if n == 0 || uint32(n) > le.Count || uint32(n) < le.Count && !features.Contains(FeaturePartiallyBoundBindingArrays) {
+		return fmt.Errorf("%w: %d elements for count %d", ErrBindingArrayLength, n, le.Count)
+	}
+	for i := range n {
+		if err := elem(i); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateBinding checks a buffer range against the binding layout.
+func (l *BufferBindingLayout) validateBinding(b BufferBinding, limits Limits, lookup BindGroupLookup) error {
+	var (
+		buf BufferDescriptor
+		ok  bool
+	)
+	if lookup.Buffer != nil && b.Buffer != 0 {
+		buf, ok = lookup.Buffer(b.Buffer)
+	}
+	if !ok {
+		return fmt.Errorf("%w: unknown buffer %#x", ErrBindingResource, b.Buffer)
+	}
+
+	usage, usageName := BufferUsageUniform, "Uniform"
+	align, maxSize := limits.MinUniformBufferOffsetAlignment, limits.MaxUniformBufferBindingSize
+	storage := l.Type == BufferBindingTypeStorage || l.Type == BufferBindingTypeReadOnlyStorage
+	if storage {
+		usage, usageName = BufferUsageStorage, "Storage"
+		align, maxSize = limits.MinStorageBufferOffsetAlignment, limits.MaxStorageBufferBindingSize
+	}
+	if !buf.Usage.Contains(usage) {
+		return fmt.Errorf("%w: buffer needs %s", ErrResourceUsage, usageName)
+	}
+	if align != 0 && b.Offset%uint64(align) != 0 {
+		return fmt.Errorf("%w: offset %d, alignment %d", ErrBufferOffsetAlignment, b.Offset, align)
+	}
+
+	if b.Offset > buf.Size {
+		return fmt.Errorf("%w: offset %d past buffer size %d", ErrBufferBindingSize, b.Offset, buf.Size)
+	}
+	size := b.Size
+	if size == 0 {
+		size = buf.Size - b.Offset
+	}
+	switch {
+	case size > buf.Size-b.Offset:
+		return fmt.Errorf("%w: %d bytes at offset %d, buffer has %d", ErrBufferBindingSize, size, b.Offset, buf.Size)
+	case size == 0 || size < l.MinBindingSize:
+		return fmt.Errorf("%w: %d bytes, MinBindingSize %d", ErrBufferBindingSize, size, l.MinBindingSize)
+	case size > maxSize:
+		return fmt.Errorf("%w: %d bytes > limit %d", ErrBufferBindingSize, size, maxSize)
+	case storage && size%4 != 0:
+		return fmt.Errorf("%w: storage binding size %d is not a multiple of 4", ErrBufferBindingSize, size)
+	}
+	return nil
+}
+
+// validateBinding checks a sampler against the binding layout.
+func (l *SamplerBindingLayout) validateBinding(s SamplerBinding, lookup BindGroupLookup) error {
+	var (
+		desc SamplerDescriptor
+		ok   bool
+	)
+	if lookup.Sampler != nil && s.Sampler != 0 {
+		desc, ok = lookup.Sampler(s.Sampler)
+	}
+	if !ok {
+		return fmt.Errorf("%w: unknown sampler %#x", ErrBindingResource, s.Sampler)
+	}
+
+	comparison := desc.Compare != CompareFunctionUndefined
+	switch l.Type {
+	case SamplerBindingTypeComparison:
+		if !comparison {
+			return fmt.Errorf("%w: Comparison binding needs a comparison sampler", ErrSamplerType)
+		}
+	case SamplerBindingTypeNonFiltering:
+		if desc.MagFilter == FilterModeLinear || desc.MinFilter == FilterModeLinear || desc.MipmapFilter == MipmapFilterModeLinear {
+			return fmt.Errorf("%w: NonFiltering binding with a filtering sampler", ErrSamplerType)
+		}
+		fallthrough
+	default:
+		if comparison {
+			return fmt.Errorf("%w: %s binding with a comparison sampler", ErrSamplerType, l.Type)
+		}
+	}
+	return nil
+}
+
+// lookupView resolves a texture view binding.
+func lookupView(v TextureViewBinding, lookup BindGroupLookup) (TextureViewInfo, error) {
+	var (
+		info TextureViewInfo
+		ok   bool
+	)
+	if lookup.TextureView != nil && v.TextureView != 0 {
+		info, ok = lookup.TextureView(v.TextureView)
+	}
+	if !ok {
+		return info, fmt.Errorf("%w: unknown texture view %#x", ErrBindingResource, v.TextureView)
+	}
+	if info.Dimension == TextureViewDimensionUndefined {
+		info.Dimension = TextureViewDimension2D
+	}
+	info.SampleCount = max(info.SampleCount, 1)
+	return info, nil
+}
+
+// validateBinding checks a sampled texture view against the binding layout.
+func (l *TextureBindingLayout) validateBinding(v TextureViewBinding, features Features, lookup BindGroupLookup) error {
+	info, err := lookupView(v, lookup)
+	if err != nil {
+		return err
+	}
+	if !info.Usage.Contains(TextureUsageTextureBinding) {
+		return fmt.Errorf("%w: texture needs TextureBinding", ErrResourceUsage)
+	}
+	if st := info.Format.SampleType(info.Aspect, features); !l.SampleType.Accepts(st) {
+		return fmt.Errorf("%w: %s view (%s, aspect %s) for %s binding", ErrTextureViewMismatch, st, info.Format, info.Aspect, l.SampleType)
+	}
+	dim := l.ViewDimension
+	if dim == TextureViewDimensionUndefined {
+		dim = TextureViewDimension2D
+	}
+	if info.Dimension != dim {
+		return fmt.Errorf("%w: view dimension %s, want %s", ErrTextureViewMismatch, info.Dimension, dim)
+	}
+	if (info.SampleCount > 1) != l.Multisampled {
+		return fmt.Errorf("%w: sample count %d, multisampled binding %v", ErrTextureViewMismatch, info.SampleCount, l.Multisampled)
+	}
+	return nil
+}
+
+// validateBinding checks a storage texture view against the binding layout.
+func (l *StorageTextureBindingLayout) validateBinding(v TextureViewBinding, lookup BindGroupLookup) error {
+	info, err := lookupView(v, lookup)
+	if err != nil {
+		return err
+	}
+	if !info.Usage.Contains(TextureUsageStorageBinding) {
+		return fmt.Errorf("%w: texture needs StorageBinding", ErrResourceUsage)
+	}
+	if info.Format != l.Format {
+		return fmt.Errorf("%w: format %s, want %s", ErrTextureViewMismatch, info.Format, l.Format)
+	}
+	dim := l.ViewDimension
+	if dim == TextureViewDimensionUndefined {
+		dim = TextureViewDimension2D
+	}
+	if info.Dimension != dim {
+		return fmt.Errorf("%w: view dimension %s, want %s", ErrTextureViewMismatch, info.Dimension, dim)
+	}
+	if info.SampleCount != 1 {
+		return fmt.Errorf("%w: storage textures must be single-sampled", ErrTextureViewMismatch)
+	}
+	return nil
+}
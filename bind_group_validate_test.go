@@ -0,0 +1,228 @@
+package gputypes
+
+import (
+	"errors"
+	"testing"
+)
+
+// testBindGroupLookup describes the handles used by the bind group tests.
+func testBindGroupLookup() BindGroupLookup {
+	buffers := map[uintptr]BufferDescriptor{
+		1: {Size: 1024, Usage: BufferUsageUniform | BufferUsageCopyDst},
+		2: {Size: 4096, Usage: BufferUsageStorage},
+		3: {Size: 1 << 20, Usage: BufferUsageUniform},
+	}
+	views := map[uintptr]TextureViewInfo{
+		10: {Format: TextureFormatRGBA8Unorm, Usage: TextureUsageTextureBinding},
+		11: {Format: TextureFormatRGBA8Unorm, Dimension: TextureViewDimensionCube, Usage: TextureUsageTextureBinding},
+		12: {Format: TextureFormatRGBA8Unorm, SampleCount: 4, Usage: TextureUsageTextureBinding},
+		13: {Format: TextureFormatR32Float, Usage: TextureUsageTextureBinding | TextureUsageStorageBinding},
+		14: {Format: TextureFormatDepth24PlusStencil8, Aspect: TextureAspectDepthOnly, Usage: TextureUsageTextureBinding},
+		15: {Format: TextureFormatRGBA8Unorm, Usage: TextureUsageRenderAttachment},
+	}
+	samplers := map[uintptr]SamplerDescriptor{
+		20: {MagFilter: FilterModeLinear, MinFilter: FilterModeLinear},
+		21: {Compare: CompareFunctionLess},
+		22: {MagFilter: FilterModeNearest},
+	}
+	return BindGroupLookup{
+		Buffer:      func(h uintptr) (BufferDescriptor, bool) { d, ok := buffers[h]; return d, ok },
+		TextureView: func(h uintptr) (TextureViewInfo, bool) { d, ok := views[h]; return d, ok },
+		Sampler:     func(h uintptr) (SamplerDescriptor, bool) { d, ok := samplers[h]; return d, ok },
+	}
+}
+
+func TestBindGroupDescriptor_Validate(t *testing.T) {
+	layout := BindGroupLayoutDescriptor{Entries: []BindGroupLayoutEntry{
+		{Binding: 0, Buffer: &BufferBindingLayout{MinBindingSize: 64}},
+		{Binding: 1, Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage}},
+		{Binding: 2, Texture: &TextureBindingLayout{}},
+		{Binding: 3, Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeFiltering}},
+		{Binding: 4, StorageTexture: &StorageTextureBindingLayout{Format: TextureFormatR32Float}},
+	}}
+	valid := func() []BindGroupEntry {
+		return []BindGroupEntry{
+			{Binding: 0, Resource: BufferBinding{Buffer: 1, Offset: 256, Size: 64}},
+			{Binding: 1, Resource: BufferBinding{Buffer: 2}},
+			{Binding: 2, Resource: TextureViewBinding{TextureView: 10}},
+			{Binding: 3, Resource: SamplerBinding{Sampler: 20}},
+			{Binding: 4, Resource: TextureViewBinding{TextureView: 13}},
+		}
+	}
+	with := func(i int, r BindingResource) []BindGroupEntry {
+		e := valid()
+		e[i].Resource = r
+		return e
+	}
+
+	tests := []struct {
+		name    string
+		entries []BindGroupEntry
+		binding uint32
+		want    error
+	}{
+		{"valid", valid(), 0, nil},
+		{"missing", valid()[:4], 4, ErrBindingMissing},
+		{"not in layout", append(valid(), BindGroupEntry{Binding: 9, Resource: SamplerBinding{Sampler: 20}}), 9, ErrBindingNotInLayout},
+		{"duplicate", append(valid(), valid()[2]), 2, ErrDuplicateBinding},
+		{"wrong kind", with(3, TextureViewBinding{TextureView: 10}), 3, ErrBindingResource},
+		{"unknown buffer", with(0, BufferBinding{Buffer: 99}), 0, ErrBindingResource},
+		{"buffer usage", with(1, BufferBinding{Buffer: 1}), 1, ErrResourceUsage},
+		{"offset alignment", with(0, BufferBinding{Buffer: 1, Offset: 64, Size: 64}), 0, ErrBufferOffsetAlignment},
+		{"below MinBindingSize", with(0, BufferBinding{Buffer: 1, Size: 32}), 0, ErrBufferBindingSize},
+		{"past end", with(0, BufferBinding{Buffer: 1, Offset: 768, Size: 512}), 0, ErrBufferBindingSize},
+		{"offset past end", with(0, BufferBinding{Buffer: 1, Offset: 2048}), 0, ErrBufferBindingSize},
+		{"uniform too large", with(0, BufferBinding{Buffer: 3}), 0, ErrBufferBindingSize},
+		{"storage size unaligned", with(1, BufferBinding{Buffer: 2, Size: 6}), 1, ErrBufferBindingSize},
+		{"view dimension", with(2, TextureViewBinding{TextureView: 11}), 2, ErrTextureViewMismatch},
+		{"multisampled view", with(2, TextureViewBinding{TextureView: 12}), 2, ErrTextureViewMismatch},
+		{"unfilterable view", with(2, TextureViewBinding{TextureView: 13}), 2, ErrTextureViewMismatch},
+		{"texture usage", with(2, TextureViewBinding{TextureView: 15}), 2, ErrResourceUsage},
+		{"comparison sampler", with(3, SamplerBinding{Sampler: 21}), 3, ErrSamplerType},
+		{"storage format", with(4, TextureViewBinding{TextureView: 10}), 4, ErrResourceUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := BindGroupDescriptor{Entries: tt.entries}
+			err := d.Validate(&layout, DefaultLimits(), 0, testBindGroupLookup())
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.want)
+			}
+			var be *BindGroupError
+			if !errors.As(err, &be) || be.Binding != tt.binding {
+				t.Errorf("Validate() error = %v, want binding %d", err, tt.binding)
+			}
+		})
+	}
+}
+
+func TestBindGroupDescriptor_ValidateBindingTypes(t *testing.T) {
+	lookup := testBindGroupLookup()
+	tests := []struct {
+		name     string
+		entry    BindGroupLayoutEntry
+		resource BindingResource
+		features Features
+		want     error
+	}{
+		{"depth as depth", BindGroupLayoutEntry{Texture: &TextureBindingLayout{SampleType: TextureSampleTypeDepth}},
+			TextureViewBinding{TextureView: 14}, 0, nil},
+		{"depth as unfilterable", BindGroupLayoutEntry{Texture: &TextureBindingLayout{SampleType: TextureSampleTypeUnfilterableFloat}},
+			TextureViewBinding{TextureView: 14}, 0, nil},
+		{"depth as float", BindGroupLayoutEntry{Texture: &TextureBindingLayout{}},
+			TextureViewBinding{TextureView: 14}, 0, ErrTextureViewMismatch},
+		{"float32 filterable", BindGroupLayoutEntry{Texture: &TextureBindingLayout{}},
+			TextureViewBinding{TextureView: 13}, Features(FeatureFloat32Filterable), nil},
+		{"multisampled", BindGroupLayoutEntry{Texture: &TextureBindingLayout{SampleType: TextureSampleTypeUnfilterableFloat, Multisampled: true}},
+			TextureViewBinding{TextureView: 12}, 0, nil},
+		{"comparison", BindGroupLayoutEntry{Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeComparison}},
+			SamplerBinding{Sampler: 21}, 0, nil},
+		{"comparison needs compare", BindGroupLayoutEntry{Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeComparison}},
+			SamplerBinding{Sampler: 22}, 0, ErrSamplerType},
+		{"non-filtering", BindGroupLayoutEntry{Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeNonFiltering}},
+			SamplerBinding{Sampler: 22}, 0, nil},
+		{"non-filtering with linear", BindGroupLayoutEntry{Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeNonFiltering}},
+			SamplerBinding{Sampler: 20}, 0, ErrSamplerType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := BindGroupLayoutDescriptor{Entries: []BindGroupLayoutEntry{tt.entry}}
+			d := BindGroupDescriptor{Entries: []BindGroupEntry{{Resource: tt.resource}}}
+			err := d.Validate(&layout, DefaultLimits(), tt.features, lookup)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextureFormat_SampleType(t *testing.T) {
+	tests := []struct {
+		format TextureFormat
+		aspect TextureAspect
+		want   TextureSampleType
+	}{
+		{TextureFormatRGBA8UnormSrgb, TextureAspectAll, TextureSampleTypeFloat},
+		{TextureFormatBC7RGBAUnorm, TextureAspectUndefined, TextureSampleTypeFloat},
+		{TextureFormatRGBA32Float, TextureAspectAll, TextureSampleTypeUnfilterableFloat},
+		{TextureFormatRG16Sint, TextureAspectAll, TextureSampleTypeSint},
+		{TextureFormatRGB10A2Uint, TextureAspectAll, TextureSampleTypeUint},
+		{TextureFormatDepth32Float, TextureAspectAll, TextureSampleTypeDepth},
+		{TextureFormatDepth24PlusStencil8, TextureAspectAll, TextureSampleTypeUndefined},
+		{TextureFormatDepth24PlusStencil8, TextureAspectStencilOnly, TextureSampleTypeUint},
+		{TextureFormatStencil8, TextureAspectAll, TextureSampleTypeUint},
+		{TextureFormatDepth16Unorm, TextureAspectStencilOnly, TextureSampleTypeUndefined},
+		{TextureFormatRGBA8Unorm, TextureAspectDepthOnly, TextureSampleTypeUndefined},
+	}
+	for _, tt := range tests {
+		if got := tt.format.SampleType(tt.aspect, 0); got != tt.want {
+			t.Errorf("%s.SampleType(%s) = %s, want %s", tt.format, tt.aspect, got, tt.want)
+		}
+	}
+	if !TextureSampleTypeUndefined.Accepts(TextureSampleTypeFloat) || TextureSampleTypeFloat.Accepts(TextureSampleTypeUnfilterableFloat) {
+		t.Error("Accepts does not read Undefined as Float or lets Float accept unfilterable views")
+	}
+}
+
+func TestBindGroupDescriptor_ValidateBindingArrays(t *testing.T) {
+	layout := BindGroupLayoutDescriptor{Entries: []BindGroupLayoutEntry{
+		{Binding: 0, Texture: &TextureBindingLayout{}, Count: 3},
+		{Binding: 1, Sampler: &SamplerBindingLayout{}, Count: 2},
+		{Binding: 2, Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage}, Count: 2},
+	}}
+	valid := func() []BindGroupEntry {
+		return []BindGroupEntry{
+			{Binding: 0, Resource: TextureViewArrayBinding{TextureViews: []uintptr{10, 10, 10}}},
+			{Binding: 1, Resource: SamplerArrayBinding{Samplers: []uintptr{20, 22}}},
+			{Binding: 2, Resource: BufferArrayBinding{Buffers: []BufferBinding{{Buffer: 2}, {Buffer: 2, Offset: 256}}}},
+		}
+	}
+	with := func(i int, r BindingResource) []BindGroupEntry {
+		e := valid()
+		e[i].Resource = r
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		entries  []BindGroupEntry
+		features Features
+		binding  uint32
+		want     error
+	}{
+		{"valid", valid(), 0, 0, nil},
+		{"partially bound", with(0, TextureViewArrayBinding{TextureViews: []uintptr{10}}), Features(FeaturePartiallyBoundBindingArrays), 0, nil},
+		{"short", with(0, TextureViewArrayBinding{TextureViews: []uintptr{10}}), 0, 0, ErrBindingArrayLength},
+		{"long", with(1, SamplerArrayBinding{Samplers: []uintptr{20, 20, 20}}), Features(FeaturePartiallyBoundBindingArrays), 1, ErrBindingArrayLength},
+		{"empty", with(1, SamplerArrayBinding{}), Features(FeaturePartiallyBoundBindingArrays), 1, ErrBindingArrayLength},
+		{"single resource", with(0, TextureViewBinding{TextureView: 10}), 0, 0, ErrBindingResource},
+		{"wrong array kind", with(1, TextureViewArrayBinding{TextureViews: []uintptr{10, 10}}), 0, 1, ErrBindingResource},
+		{"bad element", with(0, TextureViewArrayBinding{TextureViews: []uintptr{10, 11, 10}}), 0, 0, ErrTextureViewMismatch},
+		{"bad buffer element", with(2, BufferArrayBinding{Buffers: []BufferBinding{{Buffer: 2}, {Buffer: 1}}}), 0, 2, ErrResourceUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := BindGroupDescriptor{Entries: tt.entries}
+			err := d.Validate(&layout, DefaultLimits(), tt.features, testBindGroupLookup())
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.want)
+			}
+			var be *BindGroupError
+			if !errors.As(err, &be) || be.Binding != tt.binding {
+				t.Errorf("Validate() error = %v, want binding %d", err, tt.binding)
+			}
+		})
+	}
+}
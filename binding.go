@@ -10,7 +10,8 @@ type BindGroupLayoutDescriptor struct {
 
 // BindGroupLayoutEntry describes a single binding in a bind group layout.
 //
-// Exactly one of Buffer, Sampler, Texture, or StorageTexture must be set.
+// Exactly one of Buffer, Sampler, Texture, or StorageTexture must be set;
+// BindGroupLayoutDescriptor.Validate enforces this.
 type BindGroupLayoutEntry struct {
 	// Binding is the binding number (must match @binding in shader).
 	Binding uint32
@@ -24,6 +25,9 @@ type BindGroupLayoutEntry struct {
 	Texture *TextureBindingLayout
 	// StorageTexture describes a storage texture binding (nil if not storage).
 	StorageTexture *StorageTextureBindingLayout
+	// Count is the number of elements in a binding array (0 for a single
+	// binding). Arrays require FeatureBindingArrays.
+	Count uint32
 }
 
 // BufferBindingLayout describes a buffer binding in a bind group layout.
@@ -116,6 +120,8 @@ type BindGroupEntry struct {
 //   - BufferBinding for buffer resources
 //   - SamplerBinding for sampler resources
 //   - TextureViewBinding for texture view resources
+//   - BufferArrayBinding, SamplerArrayBinding and TextureViewArrayBinding
+//     for binding arrays
 type BindingResource interface {
 	// bindingResource is a marker method to identify binding resources.
 	bindingResource()
@@ -152,6 +158,33 @@ type TextureViewBinding struct {
 // bindingResource implements BindingResource.
 func (TextureViewBinding) bindingResource() {}
 
+// BufferArrayBinding binds buffer ranges to a binding array.
+type BufferArrayBinding struct {
+	// Buffers are the array elements, in index order.
+	Buffers []BufferBinding
+}
+
+// bindingResource implements BindingResource.
+func (BufferArrayBinding) bindingResource() {}
+
+// SamplerArrayBinding binds samplers to a binding array.
+type SamplerArrayBinding struct {
+	// Samplers are handles to the array elements, in index order.
+	Samplers []uintptr
+}
+
+// bindingResource implements BindingResource.
+func (SamplerArrayBinding) bindingResource() {}
+
+// TextureViewArrayBinding binds texture views to a binding array.
+type TextureViewArrayBinding struct {
+	// TextureViews are handles to the array elements, in index order.
+	TextureViews []uintptr
+}
+
+// bindingResource implements BindingResource.
+func (TextureViewArrayBinding) bindingResource() {}
+
 // PipelineLayoutDescriptor describes a pipeline layout.
 type PipelineLayoutDescriptor struct {
 	// Label is an optional debug label.
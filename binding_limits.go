@@ -0,0 +1,310 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBindingLimit means a pipeline layout exceeds one or more binding limits.
+var ErrBindingLimit = errors.New("binding limits exceeded")
+
+// BindingLimit identifies a Limits field that bounds the bindings of a
+// pipeline layout.
+type BindingLimit uint32
+
+const (
+	// BindingLimitSampledTextures is MaxSampledTexturesPerShaderStage.
+	BindingLimitSampledTextures BindingLimit = iota
+	// BindingLimitSamplers is MaxSamplersPerShaderStage.
+	BindingLimitSamplers
+	// BindingLimitStorageBuffers is MaxStorageBuffersPerShaderStage.
+	BindingLimitStorageBuffers
+	// BindingLimitStorageTextures is MaxStorageTexturesPerShaderStage.
+	BindingLimitStorageTextures
+	// BindingLimitUniformBuffers is MaxUniformBuffersPerShaderStage.
+	BindingLimitUniformBuffers
+	// BindingLimitBindingArrayElements is MaxBindingArrayElementsPerShaderStage.
+	BindingLimitBindingArrayElements
+	// BindingLimitBindingArraySamplerElements is MaxBindingArraySamplerElementsPerShaderStage.
+	BindingLimitBindingArraySamplerElements
+	// BindingLimitDynamicUniformBuffers is MaxDynamicUniformBuffersPerPipelineLayout.
+	BindingLimitDynamicUniformBuffers
+	// BindingLimitDynamicStorageBuffers is MaxDynamicStorageBuffersPerPipelineLayout.
+	BindingLimitDynamicStorageBuffers
+	// BindingLimitBindGroups is MaxBindGroups.
+	BindingLimitBindGroups
+	// BindingLimitBindGroupsPlusVertexBuffers is MaxBindGroupsPlusVertexBuffers.
+	BindingLimitBindGroupsPlusVertexBuffers
+)
+
+// String returns the name of the Limits field.
+func (l BindingLimit) String() string {
+	switch l {
+	case BindingLimitSampledTextures:
+		return "MaxSampledTexturesPerShaderStage"
+	case BindingLimitSamplers:
+		return "MaxSamplersPerShaderStage"
+	case BindingLimitStorageBuffers:
+		return "MaxStorageBuffersPerShaderStage"
+	case BindingLimitStorageTextures:
+		return "MaxStorageTexturesPerShaderStage"
+	case BindingLimitUniformBuffers:
+		return "MaxUniformBuffersPerShaderStage"
+	case BindingLimitBindingArrayElements:
+		return "MaxBindingArrayElementsPerShaderStage"
+	case BindingLimitBindingArraySamplerElements:
+		return "MaxBindingArraySamplerElementsPerShaderStage"
+	case BindingLimitDynamicUniformBuffers:
+		return "MaxDynamicUniformBuffersPerPipelineLayout"
+	case BindingLimitDynamicStorageBuffers:
+		return "MaxDynamicStorageBuffersPerPipelineLayout"
+	case BindingLimitBindGroups:
+		return "MaxBindGroups"
+	case BindingLimitBindGroupsPlusVertexBuffers:
+		return "MaxBindGroupsPlusVertexBuffers"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsPerStage reports whether the limit applies to each shader stage
+// separately rather than to the whole pipeline layout.
+func (l BindingLimit) IsPerStage() bool {
+	return l <= BindingLimitBindingArraySamplerElements
+}
+
+// Value returns the limit's value in limits.
+func (l BindingLimit) Value(limits Limits) uint32 {
+	switch l {
+	case BindingLimitSampledTextures:
+		return limits.MaxSampledTexturesPerShaderStage
+	case BindingLimitSamplers:
+		return limits.MaxSamplersPerShaderStage
+	case BindingLimitStorageBuffers:
+		return limits.MaxStorageBuffersPerShaderStage
+	case BindingLimitStorageTextures:
+		return limits.MaxStorageTexturesPerShaderStage
+	case BindingLimitUniformBuffers:
+		return limits.MaxUniformBuffersPerShaderStage
+	case BindingLimitBindingArrayElements:
+		return limits.MaxBindingArrayElementsPerShaderStage
+	case BindingLimitBindingArraySamplerElements:
+		return limits.MaxBindingArraySamplerElementsPerShaderStage
+	case BindingLimitDynamicUniformBuffers:
+		return limits.MaxDynamicUniformBuffersPerPipelineLayout
+	case BindingLimitDynamicStorageBuffers:
+		return limits.MaxDynamicStorageBuffersPerPipelineLayout
+	case BindingLimitBindGroups:
+		return limits.MaxBindGroups
+	case BindingLimitBindGroupsPlusVertexBuffers:
+		return limits.MaxBindGroupsPlusVertexBuffers
+	default:
+		return 0
+	}
+}
+
+// BindingRef identifies a bind group layout entry in a pipeline layout.
+type BindingRef struct {
+	// Group is the bind group index.
+	Group int
+	// Entry is the index into the group's Entries.
+	Entry int
+	// Binding is the entry's binding number.
+	Binding uint32
+}
+
+// BindingLimitUsage is the count of bindings against one limit, for one
+// shader stage or the whole layout.
+type BindingLimitUsage struct {
+	// Limit is the limit counted against.
+	Limit BindingLimit
+	// Stage is the shader stage for per-stage limits, or ShaderStageNone.
+	Stage ShaderStage
+	// Count is the number of bindings counted.
+	Count uint32
+	// Max is the limit's value.
+	Max uint32
+	// Entries are the contributing entries; nil for the bind group limits.
+	Entries []BindingRef
+}
+
+// Exceeded reports whether Count is above Max.
+func (u BindingLimitUsage) Exceeded() bool {
+	return u.Count > u.Max
+}
+
+// String describes the usage, for example
+// "MaxSamplersPerShaderStage (Fragment): 17 > 16".
+func (u BindingLimitUsage) String() string {
+	var b strings.Builder
+	b.WriteString(u.Limit.String())
+	if u.Stage != ShaderStageNone {
+		fmt.Fprintf(&b, " (%s)", u.Stage)
+	}
+	op := "<="
+	if u.Exceeded() {
+		op = ">"
+	}
+	fmt.Fprintf(&b, ": %d %s %d", u.Count, op, u.Max)
+	return b.String()
+}
+
+// BindingLimitReport is the result of CheckBindingLimits.
+type BindingLimitReport struct {
+	// Usage lists every limit with a non-zero count, per-stage limits in
+	// Vertex, Fragment, Compute order.
+	Usage []BindingLimitUsage
+}
+
+// Exceeded returns the usages that are over their limit.
+func (r *BindingLimitReport) Exceeded() []BindingLimitUsage {
+	var out []BindingLimitUsage
+	for _, u := range r.Usage {
+		if u.Exceeded() {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// Count returns the count against limit for stage; stage is ignored for
+// layout-wide limits.
+func (r *BindingLimitReport) Count(limit BindingLimit, stage ShaderStage) uint32 {
+	for _, u := range r.Usage {
+		if u.Limit == limit && (!limit.IsPerStage() || u.Stage == stage) {
+			return u.Count
+		}
+	}
+	return 0
+}
+
+// Err returns nil if no limit is exceeded, or an error wrapping
+// ErrBindingLimit that lists every exceeded limit and its entries.
+func (r *BindingLimitReport) Err() error {
+	exceeded := r.Exceeded()
+	if len(exceeded) == 0 {
+		return nil
+	}
+	parts := make([]string, len(exceeded))
+	for i, u := range exceeded {
+		parts[i] = u.String()
+		if len(u.Entries) > 0 {
+			refs := make([]string, len(u.Entries))
+			for j, e := range u.Entries {
+				refs[j] = fmt.Sprintf("group %d binding %d", e.Group, e.Binding)
+			}
+			parts[i] += " [" + strings.Join(refs, ", ") + "]"
+		}
+	}
+	return fmt.Errorf("gputypes: %w: %s", ErrBindingLimit, strings.Join(parts, "; "))
+}
+
+// CheckBindingLimits counts the bindings of a pipeline layout made of the
+// given bind group layouts against limits.
+//
+// Each entry counts once for every stage in its Visibility against the
+// per-stage limit of its kind: uniform buffers, storage buffers (including
+// read-only), samplers, sampled textures or storage textures. A binding
+// array counts Count times against that limit, and also against
+// MaxBindingArraySamplerElementsPerShaderStage for sampler arrays or
+// MaxBindingArrayElementsPerShaderStage for all other arrays.
+// Dynamic-offset buffers count once against the layout-wide dynamic limits.
+// The number of groups is checked against MaxBindGroups and, with
+// vertexBuffers (0 for compute pipelines), against MaxBindGroupsPlusVertexBuffers.
+func CheckBindingLimits(groups []BindGroupLayoutDescriptor, vertexBuffers uint32, limits Limits) *BindingLimitReport {
+	type key struct {
+		limit BindingLimit
+		stage ShaderStage
+	}
+	usage := make(map[key]*BindingLimitUsage)
+	add := func(limit BindingLimit, stage ShaderStage, n uint32, ref BindingRef) {
+		k := key{limit, stage}
+		u := usage[k]
+		if u == nil {
+			u = &BindingLimitUsage{Limit: limit, Stage: stage, Max: limit.Value(limits)}
+			usage[k] = u
+		}
+		u.Count += n
+		u.Entries = append(u.Entries, ref)
+	}
+
+	for g := range groups {
+		for i := range groups[g].Entries {
+			e := &groups[g].Entries[i]
+			ref := BindingRef{Group: g, Entry: i, Binding: e.Binding}
+			limit, ok := e.bindingLimit()
+			if !ok {
+				continue
+			}
+			n := e.bindingCount()
+			arrayLimit := BindingLimitBindingArrayElements
+			if limit == BindingLimitSamplers {
+				arrayLimit = BindingLimitBindingArraySamplerElements
+			}
+			for _, stage := range [3]ShaderStage{ShaderStageVertex, ShaderStageFragment, ShaderStageCompute} {
+				if e.Visibility&stage != 0 {
+					add(limit, stage, n, ref)
+					if e.Count > 0 {
+						add(arrayLimit, stage, n, ref)
+					}
+				}
+			}
+			if e.Buffer != nil && e.Buffer.HasDynamicOffset {
+				if limit == BindingLimitStorageBuffers {
+					add(BindingLimitDynamicStorageBuffers, ShaderStageNone, n, ref)
+				} else {
+					add(BindingLimitDynamicUniformBuffers, ShaderStageNone, n, ref)
+				}
+			}
+		}
+	}
+
+	report := &BindingLimitReport{}
+	for limit := range BindingLimitBindGroups {
+		if limit.IsPerStage() {
+			for _, stage := range [3]ShaderStage{ShaderStageVertex, ShaderStageFragment, ShaderStageCompute} {
+				if u := usage[key{limit, stage}]; u != nil {
+					report.Usage = append(report.Usage, *u)
+				}
+			}
+		} else if u := usage[key{limit, ShaderStageNone}]; u != nil {
+			report.Usage = append(report.Usage, *u)
+		}
+	}
+	if n := uint32(len(groups)); n > 0 {
+		report.Usage = append(report.Usage,
+			BindingLimitUsage{Limit: BindingLimitBindGroups, Count: n, Max: limits.MaxBindGroups})
+	}
+	if n := uint32(len(groups)) + vertexBuffers; n > 0 {
+		report.Usage = append(report.Usage,
+			BindingLimitUsage{Limit: BindingLimitBindGroupsPlusVertexBuffers, Count: n, Max: limits.MaxBindGroupsPlusVertexBuffers})
+	}
+	return report
+}
+
+// bindingLimit returns the per-stage limit the entry counts against. ok is
+// false for entries that set no binding kind.
+func (e *BindGroupLayoutEntry) bindingLimit() (limit BindingLimit, ok bool) {
+	switch {
+	case e.Buffer != nil:
+		if e.Buffer.Type == BufferBindingTypeStorage || e.Buffer.Type == BufferBindingTypeReadOnlyStorage {
+			return BindingLimitStorageBuffers, true
+		}
+		return BindingLimitUniformBuffers, true
+	case e.Sampler != nil:
+		return BindingLimitSamplers, true
+	case e.Texture != nil:
+		return BindingLimitSampledTextures, true
+	case e.StorageTexture != nil:
+		return BindingLimitStorageTextures, true
+	default:
+		return 0, false
+	}
+}
+
+// bindingCount returns the number of bindings the entry counts as: its
+// array Count, or 1 for a single binding.
+func (e *BindGroupLayoutEntry) bindingCount() uint32 {
+	return max(e.Count, 1)
+}
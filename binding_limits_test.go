@@ -0,0 +1,140 @@
+package gputypes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckBindingLimits(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxSamplersPerShaderStage = 2
+
+	sampler := func(binding uint32, stages ShaderStages) BindGroupLayoutEntry {
+		return BindGroupLayoutEntry{Binding: binding, Visibility: stages, Sampler: &SamplerBindingLayout{}}
+	}
+	groups := []BindGroupLayoutDescriptor{
+		{Entries: []BindGroupLayoutEntry{
+			{Binding: 0, Visibility: ShaderStagesVertexFragment, Buffer: &BufferBindingLayout{HasDynamicOffset: true}},
+			sampler(1, ShaderStageFragment),
+			{Binding: 2, Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}},
+		}},
+		{Entries: []BindGroupLayoutEntry{
+			sampler(0, ShaderStagesAll),
+			sampler(1, ShaderStageFragment),
+			{Binding: 2, Visibility: ShaderStageCompute, Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage, HasDynamicOffset: true}},
+			{Binding: 3, Visibility: ShaderStageCompute, StorageTexture: &StorageTextureBindingLayout{Format: TextureFormatR32Float}},
+		}},
+	}
+
+	report := CheckBindingLimits(groups, 2, limits)
+	counts := []struct {
+		limit BindingLimit
+		stage ShaderStage
+		want  uint32
+	}{
+		{BindingLimitUniformBuffers, ShaderStageVertex, 1},
+		{BindingLimitUniformBuffers, ShaderStageFragment, 1},
+		{BindingLimitUniformBuffers, ShaderStageCompute, 0},
+		{BindingLimitSamplers, ShaderStageFragment, 3},
+		{BindingLimitSamplers, ShaderStageVertex, 1},
+		{BindingLimitSampledTextures, ShaderStageFragment, 1},
+		{BindingLimitStorageBuffers, ShaderStageCompute, 1},
+		{BindingLimitStorageTextures, ShaderStageCompute, 1},
+		{BindingLimitDynamicUniformBuffers, ShaderStageNone, 1},
+		{BindingLimitDynamicStorageBuffers, ShaderStageNone, 1},
+		{BindingLimitBindGroups, ShaderStageNone, 2},
+		{BindingLimitBindGroupsPlusVertexBuffers, ShaderStageNone, 4},
+	}
+	for _, c := range counts {
+		if got := report.Count(c.limit, c.stage); got != c.want {
+			t.Errorf("Count(%s, %s) = %d, want %d", c.limit, c.stage, got, c.want)
+		}
+	}
+
+	exceeded := report.Exceeded()
+	if len(exceeded) != 1 {
+		t.Fatalf("Exceeded() = %v, want only fragment samplers", exceeded)
+	}
+	e := exceeded[0]
+	wantRefs := []BindingRef{{0, 1, 1}, {1, 0, 0}, {1, 1, 1}}
+	if e.Limit != BindingLimitSamplers || e.Stage != ShaderStageFragment || len(e.Entries) != len(wantRefs) {
+		t.Fatalf("exceeded = %+v", e)
+	}
+	for i, r := range wantRefs {
+		if e.Entries[i] != r {
+			t.Errorf("entry %d = %+v, want %+v", i, e.Entries[i], r)
+		}
+	}
+
+	err := report.Err()
+	if !errors.Is(err, ErrBindingLimit) {
+		t.Fatalf("Err() = %v, want ErrBindingLimit", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "MaxSamplersPerShaderStage (Fragment): 3 > 2") || !strings.Contains(msg, "group 1 binding 1") {
+		t.Errorf("Err() = %q", msg)
+	}
+}
+
+func TestCheckBindingLimits_BindGroups(t *testing.T) {
+	limits := DefaultLimits()
+	groups := make([]BindGroupLayoutDescriptor, 5)
+
+	report := CheckBindingLimits(groups, 0, limits)
+	var limitsHit []BindingLimit
+	for _, u := range report.Exceeded() {
+		limitsHit = append(limitsHit, u.Limit)
+	}
+	if len(limitsHit) != 1 || limitsHit[0] != BindingLimitBindGroups {
+		t.Errorf("5 groups exceeded %v, want [MaxBindGroups]", limitsHit)
+	}
+
+	report = CheckBindingLimits(groups[:4], limits.MaxBindGroupsPlusVertexBuffers-3, limits)
+	if ex := report.Exceeded(); len(ex) != 1 || ex[0].Limit != BindingLimitBindGroupsPlusVertexBuffers {
+		t.Errorf("groups plus vertex buffers exceeded = %v", ex)
+	}
+
+	if err := CheckBindingLimits(nil, 0, limits).Err(); err != nil {
+		t.Errorf("empty layout Err() = %v", err)
+	}
+}
+
+func TestCheckBindingLimits_BindingArrays(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxSampledTexturesPerShaderStage = 500_000
+	limits.MaxBindingArrayElementsPerShaderStage = 500_000
+	limits.MaxBindingArraySamplerElementsPerShaderStage = 1000
+	limits.MaxSamplersPerShaderStage = 1000
+
+	groups := []BindGroupLayoutDescriptor{{Entries: []BindGroupLayoutEntry{
+		{Binding: 0, Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}, Count: 4096},
+		{Binding: 1, Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}},
+		{Binding: 2, Visibility: ShaderStageFragment, Sampler: &SamplerBindingLayout{}, Count: 1024},
+		{Binding: 3, Visibility: ShaderStageFragment, Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage}, Count: 9},
+	}}}
+
+	report := CheckBindingLimits(groups, 0, limits)
+	counts := []struct {
+		limit BindingLimit
+		want  uint32
+	}{
+		{BindingLimitSampledTextures, 4097},
+		{BindingLimitSamplers, 1024},
+		{BindingLimitStorageBuffers, 9},
+		{BindingLimitBindingArrayElements, 4105},
+		{BindingLimitBindingArraySamplerElements, 1024},
+	}
+	for _, c := range counts {
+		if got := report.Count(c.limit, ShaderStageFragment); got != c.want {
+			t.Errorf("Count(%s) = %d, want %d", c.limit, got, c.want)
+		}
+	}
+
+	var hit []BindingLimit
+	for _, u := range report.Exceeded() {
+		hit = append(hit, u.Limit)
+	}
+	if len(hit) != 3 || hit[0] != BindingLimitSamplers || hit[1] != BindingLimitStorageBuffers || hit[2] != BindingLimitBindingArraySamplerElements {
+		t.Errorf("exceeded %v, want samplers, storage buffers and sampler array elements", hit)
+	}
+}
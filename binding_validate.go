@@ -0,0 +1,181 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Bind group layout validation errors.
+//
+// BindGroupLayoutDescriptor.Validate wraps these in a *BindGroupLayoutError
+// that records the offending entry; test for them with errors.Is.
+var (
+	// ErrBindingKind means an entry sets none, or more than one, of Buffer,
+	// Sampler, Texture and StorageTexture.
+	ErrBindingKind = errors.New("entry must set exactly one binding kind")
+	// ErrBindingNumber means a binding number is not below MaxBindingsPerBindGroup.
+	ErrBindingNumber = errors.New("binding number exceeds limit")
+	// ErrDuplicateBinding means two entries use the same binding number.
+	ErrDuplicateBinding = errors.New("duplicate binding number")
+	// ErrBindingVisibility means the visibility has unknown stage bits.
+	ErrBindingVisibility = errors.New("invalid binding visibility")
+	// ErrVertexWritableStorage means a writable storage buffer or storage
+	// texture is visible to the vertex stage.
+	ErrVertexWritableStorage = errors.New("writable storage visible to vertex stage")
+	// ErrMultisampledTexture means a multisampled texture binding is not 2D
+	// or has a filterable sample type.
+	ErrMultisampledTexture = errors.New("invalid multisampled texture binding")
+	// ErrStorageTextureFormat means a storage texture format does not
+	// support the requested access.
+	ErrStorageTextureFormat = errors.New("storage texture format does not support access")
+	// ErrStorageTextureDimension means a storage texture binding has a cube view dimension.
+	ErrStorageTextureDimension = errors.New("invalid storage texture view dimension")
+	// ErrDynamicOffsetCount means there are more dynamic-offset buffers than
+	// MaxDynamicUniformBuffersPerPipelineLayout or MaxDynamicStorageBuffersPerPipelineLayout.
+	ErrDynamicOffsetCount = errors.New("too many dynamic offset buffers")
+	// ErrBindingArray means a binding array (Count > 0) is used without
+	// FeatureBindingArrays or on a dynamic-offset buffer.
+	ErrBindingArray = errors.New("invalid binding array")
+)
+
+// BindGroupLayoutError reports an invalid bind group layout entry.
+type BindGroupLayoutError struct {
+	// Entry is the index into Entries.
+	Entry int
+	// Binding is the entry's binding number.
+	Binding uint32
+	// Err describes the problem and wraps one of the Err* sentinels.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BindGroupLayoutError) Error() string {
+	return fmt.Sprintf("gputypes: bind group layout entry %d (binding %d): %v", e.Entry, e.Binding, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BindGroupLayoutError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the layout against the WebGPU rules and the given limits
+// and features, and returns the first violation as a *BindGroupLayoutError.
+//
+// It checks that:
+//   - each entry sets exactly one binding kind and only known stages
+//   - binding numbers are unique and below MaxBindingsPerBindGroup
+//   - writable storage buffers and WriteOnly/ReadWrite storage textures are
+//     not visible to the vertex stage
+//   - multisampled textures are 2D with a non-filterable sample type
+//   - storage texture formats support the access (see
+//     TextureFormat.SupportsStorageAccess) and views are not cubes
+//   - dynamic-offset buffers fit MaxDynamicUniformBuffersPerPipelineLayout
+//     and MaxDynamicStorageBuffersPerPipelineLayout
+//   - binding arrays (Count > 0) have FeatureBindingArrays and no dynamic
+//     offset
+//
+// Undefined enum values are read as their WebGPU defaults: Uniform
+// buffers, Filtering samplers, Float 2D textures and WriteOnly 2D storage
+// textures.
+func (d *BindGroupLayoutDescriptor) Validate(limits Limits, features Features) error {
+	seen := make(map[uint32]bool, len(d.Entries))
+	var dynamicUniform, dynamicStorage uint32
+	for i := range d.Entries {
+		e := &d.Entries[i]
+		entryErr := func(err error) error {
+			return &BindGroupLayoutError{Entry: i, Binding: e.Binding, Err: err}
+		}
+
+		if e.Binding >= limits.MaxBindingsPerBindGroup {
+			return entryErr(fmt.Errorf("%w: %d >= %d", ErrBindingNumber, e.Binding, limits.MaxBindingsPerBindGroup))
+		}
+		if seen[e.Binding] {
+			return entryErr(ErrDuplicateBinding)
+		}
+		seen[e.Binding] = true
+		if err := e.validate(features); err != nil {
+			return entryErr(err)
+		}
+
+		if b := e.Buffer; b != nil && b.HasDynamicOffset {
+			if b.Type == BufferBindingTypeStorage || b.Type == BufferBindingTypeReadOnlyStorage {
+				dynamicStorage++
+				if dynamicStorage > limits.MaxDynamicStorageBuffersPerPipelineLayout {
+					return entryErr(fmt.Errorf("%w: %d storage > %d", ErrDynamicOffsetCount, dynamicStorage, limits.MaxDynamicStorageBuffersPerPipelineLayout))
+				}
+			} else {
+				dynamicUniform++
+				if dynamicUniform > limits.MaxDynamicUniformBuffersPerPipelineLayout {
+					return entryErr(fmt.Errorf("%w: %d uniform > %d", ErrDynamicOffsetCount, dynamicUniform, limits.MaxDynamicUniformBuffersPerPipelineLayout))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks a single entry on its own.
+func (e *BindGroupLayoutEntry) validate(features Features) error {
+	kinds := 0
+	for _, set := range [4]bool{e.Buffer != nil, e.Sampler != nil, e.Texture != nil, e.StorageTexture != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%w: %d set", ErrBindingKind, kinds)
+	}
+	if e.Visibility&^ShaderStagesAll != 0 {
+		return fmt.Errorf("%w: %#x", ErrBindingVisibility, uint32(e.Visibility))
+	}
+	if e.Count > 0 {
+		if !features.Contains(FeatureBindingArrays) {
+			return fmt.Errorf("%w: count %d requires %s", ErrBindingArray, e.Count, FeatureBindingArrays)
+		}
+		if e.Buffer != nil && e.Buffer.HasDynamicOffset {
+			return fmt.Errorf("%w: dynamic offset buffer array", ErrBindingArray)
+		}
+	}
+	vertex := e.Visibility&ShaderStageVertex != 0
+
+	switch {
+	case e.Buffer != nil:
+		switch e.Buffer.Type {
+		case BufferBindingTypeUndefined, BufferBindingTypeUniform, BufferBindingTypeReadOnlyStorage:
+		case BufferBindingTypeStorage:
+			if vertex {
+				return fmt.Errorf("%w: %s buffer", ErrVertexWritableStorage, e.Buffer.Type)
+			}
+		default:
+			return fmt.Errorf("%w: buffer type %s", ErrBindingKind, e.Buffer.Type)
+		}
+
+	case e.Texture != nil:
+		t := e.Texture
+		if t.Multisampled {
+			if t.ViewDimension != TextureViewDimensionUndefined && t.ViewDimension != TextureViewDimension2D {
+				return fmt.Errorf("%w: view dimension %s", ErrMultisampledTexture, t.ViewDimension)
+			}
+			if t.SampleType == TextureSampleTypeUndefined || t.SampleType == TextureSampleTypeFloat {
+				return fmt.Errorf("%w: filterable sample type", ErrMultisampledTexture)
+			}
+		}
+
+	case e.StorageTexture != nil:
+		st := e.StorageTexture
+		access := st.Access
+		if access == StorageTextureAccessUndefined {
+			access = StorageTextureAccessWriteOnly
+		}
+		if vertex && access != StorageTextureAccessReadOnly {
+			return fmt.Errorf("%w: %s storage texture", ErrVertexWritableStorage, access)
+		}
+		if !st.Format.SupportsStorageAccess(access, features) {
+			return fmt.Errorf("%w: %s with %s access", ErrStorageTextureFormat, st.Format, access)
+		}
+		if st.ViewDimension == TextureViewDimensionCube || st.ViewDimension == TextureViewDimensionCubeArray {
+			return fmt.Errorf("%w: %s", ErrStorageTextureDimension, st.ViewDimension)
+		}
+	}
+	return nil
+}
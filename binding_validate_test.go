@@ -0,0 +1,136 @@
+package gputypes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBindGroupLayoutDescriptor_Validate(t *testing.T) {
+	uniform := func(binding uint32, dynamic bool) BindGroupLayoutEntry {
+		return BindGroupLayoutEntry{Binding: binding, Visibility: ShaderStagesVertexFragment,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeUniform, HasDynamicOffset: dynamic}}
+	}
+	storage := func(binding uint32, dynamic bool) BindGroupLayoutEntry {
+		return BindGroupLayoutEntry{Binding: binding, Visibility: ShaderStageCompute,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeStorage, HasDynamicOffset: dynamic}}
+	}
+	storageTexture := func(access StorageTextureAccess, format TextureFormat, stages ShaderStages) BindGroupLayoutEntry {
+		return BindGroupLayoutEntry{Visibility: stages,
+			StorageTexture: &StorageTextureBindingLayout{Access: access, Format: format}}
+	}
+
+	tests := []struct {
+		name     string
+		entries  []BindGroupLayoutEntry
+		features Features
+		entry    int
+		want     error
+	}{
+		{"empty", nil, 0, 0, nil},
+		{"typical", []BindGroupLayoutEntry{
+			uniform(0, false),
+			{Binding: 1, Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}},
+			{Binding: 2, Visibility: ShaderStageFragment, Sampler: &SamplerBindingLayout{}},
+			{Binding: 5, Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{
+				SampleType: TextureSampleTypeUnfilterableFloat, Multisampled: true}},
+		}, 0, 0, nil},
+		{"read-only storage in vertex", []BindGroupLayoutEntry{
+			{Visibility: ShaderStageVertex, Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage}},
+		}, 0, 0, nil},
+		{"read-write storage texture", []BindGroupLayoutEntry{
+			storageTexture(StorageTextureAccessReadWrite, TextureFormatR32Float, ShaderStageCompute),
+		}, 0, 0, nil},
+
+		{"no kind", []BindGroupLayoutEntry{{Visibility: ShaderStageFragment}}, 0, 0, ErrBindingKind},
+		{"two kinds", []BindGroupLayoutEntry{
+			uniform(0, false),
+			{Binding: 1, Sampler: &SamplerBindingLayout{}, Texture: &TextureBindingLayout{}},
+		}, 0, 1, ErrBindingKind},
+		{"binding limit", []BindGroupLayoutEntry{uniform(1000, false)}, 0, 0, ErrBindingNumber},
+		{"duplicate", []BindGroupLayoutEntry{uniform(3, false), uniform(3, false)}, 0, 1, ErrDuplicateBinding},
+		{"unknown stage", []BindGroupLayoutEntry{{Visibility: 0x10, Sampler: &SamplerBindingLayout{}}}, 0, 0, ErrBindingVisibility},
+		{"storage buffer in vertex", []BindGroupLayoutEntry{
+			{Visibility: ShaderStagesAll, Buffer: &BufferBindingLayout{Type: BufferBindingTypeStorage}},
+		}, 0, 0, ErrVertexWritableStorage},
+		{"storage texture in vertex", []BindGroupLayoutEntry{
+			storageTexture(StorageTextureAccessUndefined, TextureFormatRGBA8Unorm, ShaderStageVertex),
+		}, 0, 0, ErrVertexWritableStorage},
+		{"multisampled float", []BindGroupLayoutEntry{
+			{Texture: &TextureBindingLayout{Multisampled: true}},
+		}, 0, 0, ErrMultisampledTexture},
+		{"multisampled array", []BindGroupLayoutEntry{
+			{Texture: &TextureBindingLayout{SampleType: TextureSampleTypeDepth, ViewDimension: TextureViewDimension2DArray, Multisampled: true}},
+		}, 0, 0, ErrMultisampledTexture},
+		{"read-write rgba8", []BindGroupLayoutEntry{
+			storageTexture(StorageTextureAccessReadWrite, TextureFormatRGBA8Unorm, ShaderStageCompute),
+		}, 0, 0, ErrStorageTextureFormat},
+		{"bgra8 without feature", []BindGroupLayoutEntry{
+			storageTexture(StorageTextureAccessWriteOnly, TextureFormatBGRA8Unorm, ShaderStageCompute),
+		}, 0, 0, ErrStorageTextureFormat},
+		{"cube storage", []BindGroupLayoutEntry{{Visibility: ShaderStageCompute, StorageTexture: &StorageTextureBindingLayout{
+			Format: TextureFormatR32Float, ViewDimension: TextureViewDimensionCube}}}, 0, 0, ErrStorageTextureDimension},
+		{"dynamic uniforms", []BindGroupLayoutEntry{
+			uniform(0, true), uniform(1, true), uniform(2, true), uniform(3, true),
+			uniform(4, true), uniform(5, true), uniform(6, true), uniform(7, true), uniform(8, true),
+		}, 0, 8, ErrDynamicOffsetCount},
+		{"dynamic storage", []BindGroupLayoutEntry{
+			storage(0, true), storage(1, false), storage(2, true), storage(3, true), storage(4, true), storage(5, true),
+		}, 0, 5, ErrDynamicOffsetCount},
+		{"binding array", []BindGroupLayoutEntry{
+			{Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}, Count: 64},
+		}, Features(FeatureBindingArrays), 0, nil},
+		{"binding array without feature", []BindGroupLayoutEntry{
+			{Visibility: ShaderStageFragment, Texture: &TextureBindingLayout{}, Count: 64},
+		}, 0, 0, ErrBindingArray},
+		{"dynamic buffer array", []BindGroupLayoutEntry{
+			{Visibility: ShaderStageCompute, Buffer: &BufferBindingLayout{HasDynamicOffset: true}, Count: 2},
+		}, Features(FeatureBindingArrays), 0, ErrBindingArray},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := BindGroupLayoutDescriptor{Entries: tt.entries}
+			err := d.Validate(DefaultLimits(), tt.features)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.want)
+			}
+			var le *BindGroupLayoutError
+			if !errors.As(err, &le) || le.Entry != tt.entry {
+				t.Errorf("Validate() error = %#v, want entry %d", err, tt.entry)
+			}
+		})
+	}
+
+	bgra := BindGroupLayoutDescriptor{Entries: []BindGroupLayoutEntry{
+		storageTexture(StorageTextureAccessWriteOnly, TextureFormatBGRA8Unorm, ShaderStageCompute),
+	}}
+	if err := bgra.Validate(DefaultLimits(), Features(FeatureBGRA8UnormStorage)); err != nil {
+		t.Errorf("BGRA8Unorm storage with feature error = %v", err)
+	}
+}
+
+func TestTextureFormat_SupportsStorageAccess(t *testing.T) {
+	tests := []struct {
+		format TextureFormat
+		access StorageTextureAccess
+		want   bool
+	}{
+		{TextureFormatRGBA8Unorm, StorageTextureAccessWriteOnly, true},
+		{TextureFormatRGBA8Unorm, StorageTextureAccessUndefined, true},
+		{TextureFormatRGBA8Unorm, StorageTextureAccessReadWrite, false},
+		{TextureFormatR32Uint, StorageTextureAccessReadWrite, true},
+		{TextureFormatRGBA8UnormSrgb, StorageTextureAccessWriteOnly, false},
+		{TextureFormatDepth32Float, StorageTextureAccessReadOnly, false},
+		{TextureFormatBGRA8Unorm, StorageTextureAccessReadOnly, false},
+	}
+	for _, tt := range tests {
+		if got := tt.format.SupportsStorageAccess(tt.access, Features(FeatureBGRA8UnormStorage)); got != tt.want {
+			t.Errorf("%s.SupportsStorageAccess(%s) = %v, want %v", tt.format, tt.access, got, tt.want)
+		}
+	}
+}
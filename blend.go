@@ -0,0 +1,148 @@
+package gputypes
+
+// Apply computes the blended color written to a render target, following
+// the WebGPU blend equation.
+//
+// src is the fragment shader output, dst the current target value and
+// constant the render pass blend constant. For Min and Max operations the
+// factors are ignored. An undefined SrcFactor, DstFactor or Operation is
+// read as One, Zero or Add, following webgpu.h, so BlendState{} replaces
+// the destination.
+//
+// Values are not clamped; callers emulating normalized targets should clamp
+// src, dst and the result to the format's range.
+func (b BlendState) Apply(src, dst, constant Color) Color {
+	rgb := func(s, d, sf, df float64) float64 {
+		return b.Color.combine(s*sf, d*df, s, d)
+	}
+	srcF := b.Color.srcFactor().colorFactor(src, dst, constant)
+	dstF := b.Color.dstFactor().colorFactor(src, dst, constant)
+	return Color{
+		R: rgb(src.R, dst.R, srcF.R, dstF.R),
+		G: rgb(src.G, dst.G, srcF.G, dstF.G),
+		B: rgb(src.B, dst.B, srcF.B, dstF.B),
+		A: b.Alpha.combine(
+			src.A*b.Alpha.srcFactor().alphaFactor(src, dst, constant),
+			dst.A*b.Alpha.dstFactor().alphaFactor(src, dst, constant),
+			src.A, dst.A),
+	}
+}
+
+// Apply returns value with the channels not selected by m taken from dst,
+// as a render target keeps them unchanged.
+func (m ColorWriteMask) Apply(value, dst Color) Color {
+	if m&ColorWriteMaskRed == 0 {
+		value.R = dst.R
+	}
+	if m&ColorWriteMaskGreen == 0 {
+		value.G = dst.G
+	}
+	if m&ColorWriteMaskBlue == 0 {
+		value.B = dst.B
+	}
+	if m&ColorWriteMaskAlpha == 0 {
+		value.A = dst.A
+	}
+	return value
+}
+
+// Apply computes the value written to the target for a fragment output src
+// over the current value dst: Blend (or replacement if nil) followed by
+// WriteMask.
+func (t ColorTargetState) Apply(src, dst, constant Color) Color {
+	out := src
+	if t.Blend != nil {
+		out = t.Blend.Apply(src, dst, constant)
+	}
+	return t.WriteMask.Apply(out, dst)
+}
+
+// srcFactor returns SrcFactor with Undefined read as One.
+func (bc BlendComponent) srcFactor() BlendFactor {
+	if bc.SrcFactor == BlendFactorUndefined {
+		return BlendFactorOne
+	}
+	return bc.SrcFactor
+}
+
+// dstFactor returns DstFactor with Undefined read as Zero.
+func (bc BlendComponent) dstFactor() BlendFactor {
+	if bc.DstFactor == BlendFactorUndefined {
+		return BlendFactorZero
+	}
+	return bc.DstFactor
+}
+
+// combine applies the operation to the weighted source and destination.
+// s and d are the unweighted values used by Min and Max.
+func (bc BlendComponent) combine(ws, wd, s, d float64) float64 {
+	switch bc.Operation {
+	case BlendOperationSubtract:
+		return ws - wd
+	case BlendOperationReverseSubtract:
+		return wd - ws
+	case BlendOperationMin:
+		return min(s, d)
+	case BlendOperationMax:
+		return max(s, d)
+	default:
+		return ws + wd
+	}
+}
+
+// colorFactor returns the per-channel weights of f for the RGB channels.
+// Only R, G and B of the result are meaningful.
+func (f BlendFactor) colorFactor(src, dst, constant Color) Color {
+	splat := func(v float64) Color { return Color{R: v, G: v, B: v} }
+	oneMinus := func(c Color) Color { return Color{R: 1 - c.R, G: 1 - c.G, B: 1 - c.B} }
+	switch f {
+	case BlendFactorOne:
+		return splat(1)
+	case BlendFactorSrc:
+		return src
+	case BlendFactorOneMinusSrc:
+		return oneMinus(src)
+	case BlendFactorSrcAlpha:
+		return splat(src.A)
+	case BlendFactorOneMinusSrcAlpha:
+		return splat(1 - src.A)
+	case BlendFactorDst:
+		return dst
+	case BlendFactorOneMinusDst:
+		return oneMinus(dst)
+	case BlendFactorDstAlpha:
+		return splat(dst.A)
+	case BlendFactorOneMinusDstAlpha:
+		return splat(1 - dst.A)
+	case BlendFactorSrcAlphaSaturated:
+		return splat(min(src.A, 1-dst.A))
+	case BlendFactorConstant:
+		return constant
+	case BlendFactorOneMinusConstant:
+		return oneMinus(constant)
+	default:
+		return splat(0)
+	}
+}
+
+// alphaFactor returns the weight of f for the alpha channel.
+func (f BlendFactor) alphaFactor(src, dst, constant Color) float64 {
+	switch f {
+	case BlendFactorOne, BlendFactorSrcAlphaSaturated:
+		return 1
+	case BlendFactorSrc, BlendFactorSrcAlpha:
+		return src.A
+	case BlendFactorOneMinusSrc, BlendFactorOneMinusSrcAlpha:
+		return 1 - src.A
+	case BlendFactorDst, BlendFactorDstAlpha:
+		return dst.A
+	case BlendFactorOneMinusDst, BlendFactorOneMinusDstAlpha:
+		return 1 - dst.A
+	case BlendFactorConstant:
+		return constant.A
+	case BlendFactorOneMinusConstant:
+		return 1 - constant.A
+	default:
+		return 0
+	}
+}
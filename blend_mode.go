@@ -0,0 +1,280 @@
+package gputypes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlendMode names a compositing operation that fixed-function blending can
+// express: the Porter-Duff operators plus common artistic modes.
+//
+// The zero value is BlendModeSrcOver, the default 2D compositing mode.
+// Premultiplied returns the BlendState for premultiplied-alpha colors;
+// Straight returns the BlendState for straight-alpha shader output blended
+// into a premultiplied target, where one exists.
+type BlendMode uint32
+
+const (
+	// BlendModeSrcOver draws the source over the destination.
+	BlendModeSrcOver BlendMode = iota
+	// BlendModeDstOver draws the destination over the source.
+	BlendModeDstOver
+	// BlendModeSrcIn keeps the source where the destination is opaque.
+	BlendModeSrcIn
+	// BlendModeDstIn keeps the destination where the source is opaque.
+	BlendModeDstIn
+	// BlendModeSrcOut keeps the source where the destination is transparent.
+	BlendModeSrcOut
+	// BlendModeDstOut keeps the destination where the source is transparent.
+	BlendModeDstOut
+	// BlendModeSrcAtop draws the source over the destination, only where the destination is opaque.
+	BlendModeSrcAtop
+	// BlendModeDstAtop draws the destination over the source, only where the source is opaque.
+	BlendModeDstAtop
+	// BlendModeXor keeps the source and destination where the other is transparent.
+	BlendModeXor
+	// BlendModePlus adds source and destination, including alpha.
+	BlendModePlus
+	// BlendModeClear clears the destination to transparent black.
+	BlendModeClear
+	// BlendModeSrc replaces the destination with the source.
+	BlendModeSrc
+	// BlendModeDst keeps the destination unchanged.
+	BlendModeDst
+	// BlendModeAdditive adds the source color to the destination, keeping destination alpha.
+	BlendModeAdditive
+	// BlendModeMultiply multiplies source and destination colors (exact for an opaque destination).
+	BlendModeMultiply
+	// BlendModeScreen computes src + dst - src*dst (exact for an opaque destination).
+	BlendModeScreen
+	// BlendModeMin takes the per-channel minimum, including alpha.
+	BlendModeMin
+	// BlendModeMax takes the per-channel maximum, including alpha.
+	BlendModeMax
+	// BlendModeDarken takes the per-channel minimum color with source-over alpha.
+	BlendModeDarken
+	// BlendModeLighten takes the per-channel maximum color with source-over alpha.
+	BlendModeLighten
+
+	blendModeCount
+)
+
+// String returns the blend mode name.
+func (m BlendMode) String() string {
+	switch m {
+	case BlendModeSrcOver:
+		return "SrcOver"
+	case BlendModeDstOver:
+		return "DstOver"
+	case BlendModeSrcIn:
+		return "SrcIn"
+	case BlendModeDstIn:
+		return "DstIn"
+	case BlendModeSrcOut:
+		return "SrcOut"
+	case BlendModeDstOut:
+		return "DstOut"
+	case BlendModeSrcAtop:
+		return "SrcAtop"
+	case BlendModeDstAtop:
+		return "DstAtop"
+	case BlendModeXor:
+		return "Xor"
+	case BlendModePlus:
+		return "Plus"
+	case BlendModeClear:
+		return "Clear"
+	case BlendModeSrc:
+		return "Src"
+	case BlendModeDst:
+		return "Dst"
+	case BlendModeAdditive:
+		return "Additive"
+	case BlendModeMultiply:
+		return "Multiply"
+	case BlendModeScreen:
+		return "Screen"
+	case BlendModeMin:
+		return "Min"
+	case BlendModeMax:
+		return "Max"
+	case BlendModeDarken:
+		return "Darken"
+	case BlendModeLighten:
+		return "Lighten"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseBlendMode returns the blend mode with the given name. Matching
+// ignores case, '-' and '_', so "SrcOver", "src-over" and "SRC_OVER" are
+// all accepted.
+func ParseBlendMode(name string) (BlendMode, error) {
+	key := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	for m := range blendModeCount {
+		if strings.ToLower(m.String()) == key {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("gputypes: unknown blend mode %q", name)
+}
+
+// BlendModes returns every defined blend mode in declaration order.
+func BlendModes() []BlendMode {
+	modes := make([]BlendMode, blendModeCount)
+	for i := range modes {
+		modes[i] = BlendMode(i)
+	}
+	return modes
+}
+
+// IsPorterDuff returns true for the Porter-Duff compositing operators.
+func (m BlendMode) IsPorterDuff() bool {
+	return m <= BlendModeDst
+}
+
+// Premultiplied returns the blend state implementing m for premultiplied
+// source and destination colors. It returns BlendState{} for unknown modes.
+//
+// BlendModeSrcOver.Premultiplied() equals BlendStatePremultiplied().
+func (m BlendMode) Premultiplied() BlendState {
+	pd := func(src, dst BlendFactor) BlendState {
+		c := BlendComponent{SrcFactor: src, DstFactor: dst, Operation: BlendOperationAdd}
+		return BlendState{Color: c, Alpha: c}
+	}
+	srcOverAlpha := BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOneMinusSrcAlpha, Operation: BlendOperationAdd}
+	op := func(color BlendOperation, alpha BlendComponent) BlendState {
+		return BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOne, Operation: color},
+			Alpha: alpha,
+		}
+	}
+
+	switch m {
+	case BlendModeSrcOver:
+		return pd(BlendFactorOne, BlendFactorOneMinusSrcAlpha)
+	case BlendModeDstOver:
+		return pd(BlendFactorOneMinusDstAlpha, BlendFactorOne)
+	case BlendModeSrcIn:
+		return pd(BlendFactorDstAlpha, BlendFactorZero)
+	case BlendModeDstIn:
+		return pd(BlendFactorZero, BlendFactorSrcAlpha)
+	case BlendModeSrcOut:
+		return pd(BlendFactorOneMinusDstAlpha, BlendFactorZero)
+	case BlendModeDstOut:
+		return pd(BlendFactorZero, BlendFactorOneMinusSrcAlpha)
+	case BlendModeSrcAtop:
+		return pd(BlendFactorDstAlpha, BlendFactorOneMinusSrcAlpha)
+	case BlendModeDstAtop:
+		return pd(BlendFactorOneMinusDstAlpha, BlendFactorSrcAlpha)
+	case BlendModeXor:
+		return pd(BlendFactorOneMinusDstAlpha, BlendFactorOneMinusSrcAlpha)
+	case BlendModePlus:
+		return pd(BlendFactorOne, BlendFactorOne)
+	case BlendModeClear:
+		return pd(BlendFactorZero, BlendFactorZero)
+	case BlendModeSrc:
+		return pd(BlendFactorOne, BlendFactorZero)
+	case BlendModeDst:
+		return pd(BlendFactorZero, BlendFactorOne)
+	case BlendModeAdditive:
+		return BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOne, Operation: BlendOperationAdd},
+			Alpha: BlendComponent{SrcFactor: BlendFactorZero, DstFactor: BlendFactorOne, Operation: BlendOperationAdd},
+		}
+	case BlendModeMultiply:
+		return BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorDst, DstFactor: BlendFactorOneMinusSrcAlpha, Operation: BlendOperationAdd},
+			Alpha: srcOverAlpha,
+		}
+	case BlendModeScreen:
+		return BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOneMinusSrc, Operation: BlendOperationAdd},
+			Alpha: srcOverAlpha,
+		}
+	case BlendModeMin:
+		return op(BlendOperationMin, BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOne, Operation: BlendOperationMin})
+	case BlendModeMax:
+		return op(BlendOperationMax, BlendComponent{SrcFactor: BlendFactorOne, DstFactor: BlendFactorOne, Operation: BlendOperationMax})
+	case BlendModeDarken:
+		return op(BlendOperationMin, srcOverAlpha)
+	case BlendModeLighten:
+		return op(BlendOperationMax, srcOverAlpha)
+	default:
+		return BlendState{}
+	}
+}
+
+// Straight returns the blend state implementing m for a straight-alpha
+// source (as most shaders output) blended into a premultiplied destination.
+//
+// The source color is weighted by source alpha through the color source
+// factor, so this is only possible when the premultiplied state uses a
+// source factor of One or Zero and a destination factor that does not read
+// the source color. ok is false for modes that need anything else, such as
+// SrcIn or Multiply. Min, Max, Darken and Lighten compare the colors as
+// given and are returned unchanged.
+//
+// BlendModeSrcOver.Straight() equals BlendStateAlpha().
+func (m BlendMode) Straight() (state BlendState, ok bool) {
+	state = m.Premultiplied()
+	if m >= blendModeCount {
+		return BlendState{}, false
+	}
+	c := &state.Color
+	if c.Operation == BlendOperationMin || c.Operation == BlendOperationMax {
+		return state, true
+	}
+	switch c.DstFactor {
+	case BlendFactorSrc, BlendFactorOneMinusSrc:
+		return BlendState{}, false
+	}
+	switch c.SrcFactor {
+	case BlendFactorOne:
+		c.SrcFactor = BlendFactorSrcAlpha
+	case BlendFactorZero:
+	default:
+		return BlendState{}, false
+	}
+	return state, true
+}
+
+// Mode identifies the catalog blend mode b implements.
+//
+// Undefined factors and operations are read as in Apply, and factors of Min
+// and Max operations are ignored. When the straight and premultiplied
+// variants of a mode are the same state, premultiplied is reported true.
+// ok is false if b matches no catalog entry.
+func (b BlendState) Mode() (mode BlendMode, premultiplied, ok bool) {
+	key := b.canonical()
+	for m := range blendModeCount {
+		if m.Premultiplied().canonical() == key {
+			return m, true, true
+		}
+	}
+	for m := range blendModeCount {
+		if s, sok := m.Straight(); sok && s.canonical() == key {
+			return m, false, true
+		}
+	}
+	return 0, false, false
+}
+
+// canonical returns b with undefined values replaced by their defaults and
+// the ignored factors of Min and Max operations cleared, so equivalent
+// states compare equal.
+func (b BlendState) canonical() BlendState {
+	return BlendState{Color: b.Color.canonical(), Alpha: b.Alpha.canonical()}
+}
+
+// canonical returns bc in the normalized form used by BlendState.canonical.
+func (bc BlendComponent) canonical() BlendComponent {
+	switch bc.Operation {
+	case BlendOperationMin, BlendOperationMax:
+		return BlendComponent{Operation: bc.Operation}
+	case BlendOperationUndefined:
+		bc.Operation = BlendOperationAdd
+	}
+	return BlendComponent{SrcFactor: bc.srcFactor(), DstFactor: bc.dstFactor(), Operation: bc.Operation}
+}
@@ -0,0 +1,139 @@
+package gputypes
+
+import "testing"
+
+func TestBlendMode_StringParse(t *testing.T) {
+	for _, m := range BlendModes() {
+		if m.String() == "Unknown" {
+			t.Errorf("BlendMode(%d).String() = Unknown", m)
+		}
+		got, err := ParseBlendMode(m.String())
+		if err != nil || got != m {
+			t.Errorf("ParseBlendMode(%q) = %v, %v; want %v", m.String(), got, err, m)
+		}
+	}
+	for name, want := range map[string]BlendMode{"src-over": BlendModeSrcOver, "DST_ATOP": BlendModeDstAtop, "xor": BlendModeXor} {
+		if got, err := ParseBlendMode(name); err != nil || got != want {
+			t.Errorf("ParseBlendMode(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseBlendMode("overlay"); err == nil {
+		t.Error("ParseBlendMode(overlay) succeeded")
+	}
+	if BlendMode(100).String() != "Unknown" {
+		t.Error("out-of-range BlendMode has a name")
+	}
+}
+
+func TestBlendMode_Presets(t *testing.T) {
+	if BlendModeSrcOver.Premultiplied() != BlendStatePremultiplied() {
+		t.Error("SrcOver.Premultiplied() != BlendStatePremultiplied()")
+	}
+	if s, ok := BlendModeSrcOver.Straight(); !ok || s != BlendStateAlpha() {
+		t.Error("SrcOver.Straight() != BlendStateAlpha()")
+	}
+	if BlendModeSrc.Premultiplied() != BlendStateReplace() {
+		t.Error("Src.Premultiplied() != BlendStateReplace()")
+	}
+	for _, m := range []BlendMode{BlendModeDstOver, BlendModeSrcIn, BlendModeSrcOut, BlendModeSrcAtop, BlendModeDstAtop, BlendModeXor, BlendModeMultiply, BlendModeScreen} {
+		if _, ok := m.Straight(); ok {
+			t.Errorf("%s.Straight() reported expressible", m)
+		}
+	}
+}
+
+func TestBlendMode_PorterDuff(t *testing.T) {
+	src := Color{R: 0.6, G: 0.3, B: 0.1, A: 0.75}.Premultiplied()
+	dst := Color{R: 0.2, G: 0.5, B: 0.9, A: 0.4}.Premultiplied()
+	sa, da := src.A, dst.A
+
+	// Fa and Fb from Porter & Duff, "Compositing Digital Images" (1984).
+	fractions := map[BlendMode][2]float64{
+		BlendModeClear:   {0, 0},
+		BlendModeSrc:     {1, 0},
+		BlendModeDst:     {0, 1},
+		BlendModeSrcOver: {1, 1 - sa},
+		BlendModeDstOver: {1 - da, 1},
+		BlendModeSrcIn:   {da, 0},
+		BlendModeDstIn:   {0, sa},
+		BlendModeSrcOut:  {1 - da, 0},
+		BlendModeDstOut:  {0, 1 - sa},
+		BlendModeSrcAtop: {da, 1 - sa},
+		BlendModeDstAtop: {1 - da, sa},
+		BlendModeXor:     {1 - da, 1 - sa},
+		BlendModePlus:    {1, 1},
+	}
+	for _, m := range BlendModes() {
+		if !m.IsPorterDuff() {
+			continue
+		}
+		f, ok := fractions[m]
+		if !ok {
+			t.Fatalf("no reference fractions for %s", m)
+		}
+		want := Color{
+			R: src.R*f[0] + dst.R*f[1],
+			G: src.G*f[0] + dst.G*f[1],
+			B: src.B*f[0] + dst.B*f[1],
+			A: src.A*f[0] + dst.A*f[1],
+		}
+		if got := m.Premultiplied().Apply(src, dst, Color{}); !colorsClose(got, want) {
+			t.Errorf("%s: Apply() = %+v, want %+v", m, got, want)
+		}
+	}
+}
+
+func TestBlendMode_StraightMatchesPremultiplied(t *testing.T) {
+	src := Color{R: 0.6, G: 0.3, B: 0.1, A: 0.75}
+	dst := Color{R: 0.2, G: 0.5, B: 0.9, A: 0.4}.Premultiplied()
+	for _, m := range BlendModes() {
+		s, ok := m.Straight()
+		if !ok || m == BlendModeMin || m == BlendModeMax || m == BlendModeDarken || m == BlendModeLighten {
+			continue
+		}
+		want := m.Premultiplied().Apply(src.Premultiplied(), dst, Color{})
+		if got := s.Apply(src, dst, Color{}); !colorsClose(got, want) {
+			t.Errorf("%s: straight = %+v, premultiplied = %+v", m, got, want)
+		}
+	}
+}
+
+func TestBlendState_Mode(t *testing.T) {
+	for _, m := range BlendModes() {
+		got, premul, ok := m.Premultiplied().Mode()
+		if !ok || got != m || !premul {
+			t.Errorf("%s.Premultiplied().Mode() = %v, %v, %v", m, got, premul, ok)
+		}
+		if s, sok := m.Straight(); sok && s != m.Premultiplied() {
+			if got, premul, ok := s.Mode(); !ok || got != m || premul {
+				t.Errorf("%s.Straight().Mode() = %v, %v, %v", m, got, premul, ok)
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		state   BlendState
+		want    BlendMode
+		premul  bool
+		matched bool
+	}{
+		{"zero value", BlendState{}, BlendModeSrc, true, true},
+		{"alpha preset", BlendStateAlpha(), BlendModeSrcOver, false, true},
+		{"min ignores factors", BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorSrc, DstFactor: BlendFactorDst, Operation: BlendOperationMin},
+			Alpha: BlendComponent{Operation: BlendOperationMin},
+		}, BlendModeMin, true, true},
+		{"unmatched", BlendState{
+			Color: BlendComponent{SrcFactor: BlendFactorConstant, DstFactor: BlendFactorOneMinusConstant, Operation: BlendOperationAdd},
+		}, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, premul, ok := tt.state.Mode()
+			if got != tt.want || premul != tt.premul || ok != tt.matched {
+				t.Errorf("Mode() = %v, %v, %v; want %v, %v, %v", got, premul, ok, tt.want, tt.premul, tt.matched)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package gputypes
+
+import (
+	"math"
+	"testing"
+)
+
+func colorsClose(a, b Color) bool {
+	const eps = 1e-9
+	return math.Abs(a.R-b.R) < eps && math.Abs(a.G-b.G) < eps &&
+		math.Abs(a.B-b.B) < eps && math.Abs(a.A-b.A) < eps
+}
+
+func TestBlendState_Apply(t *testing.T) {
+	src := Color{R: 0.8, G: 0.4, B: 0.2, A: 0.5}
+	dst := Color{R: 0.2, G: 0.6, B: 1.0, A: 0.25}
+	constant := Color{R: 0.5, G: 0.25, B: 1, A: 0.75}
+
+	same := func(src, dst BlendFactor, op BlendOperation) BlendState {
+		c := BlendComponent{SrcFactor: src, DstFactor: dst, Operation: op}
+		return BlendState{Color: c, Alpha: c}
+	}
+
+	tests := []struct {
+		name  string
+		state BlendState
+		want  Color
+	}{
+		{"zero value replaces", BlendState{}, src},
+		{"replace", BlendStateReplace(), src},
+		{"alpha", BlendStateAlpha(), Color{R: 0.5, G: 0.5, B: 0.6, A: 0.625}},
+		{"premultiplied", BlendStatePremultiplied(), Color{R: 0.9, G: 0.7, B: 0.7, A: 0.625}},
+		{"additive", same(BlendFactorOne, BlendFactorOne, BlendOperationAdd), Color{R: 1.0, G: 1.0, B: 1.2, A: 0.75}},
+		{"multiply", same(BlendFactorDst, BlendFactorZero, BlendOperationAdd), Color{R: 0.16, G: 0.24, B: 0.2, A: 0.125}},
+		{"one minus src", same(BlendFactorOneMinusSrc, BlendFactorZero, BlendOperationAdd), Color{R: 0.16, G: 0.24, B: 0.16, A: 0.25}},
+		{"one minus dst", same(BlendFactorZero, BlendFactorOneMinusDst, BlendOperationAdd), Color{R: 0.16, G: 0.24, B: 0, A: 0.1875}},
+		{"dst alpha", same(BlendFactorDstAlpha, BlendFactorOneMinusDstAlpha, BlendOperationAdd), Color{R: 0.35, G: 0.55, B: 0.8, A: 0.3125}},
+		{"subtract", same(BlendFactorOne, BlendFactorOne, BlendOperationSubtract), Color{R: 0.6, G: -0.2, B: -0.8, A: 0.25}},
+		{"reverse subtract", same(BlendFactorOne, BlendFactorOne, BlendOperationReverseSubtract), Color{R: -0.6, G: 0.2, B: 0.8, A: -0.25}},
+		{"min ignores factors", same(BlendFactorZero, BlendFactorZero, BlendOperationMin), Color{R: 0.2, G: 0.4, B: 0.2, A: 0.25}},
+		{"max ignores factors", same(BlendFactorZero, BlendFactorZero, BlendOperationMax), Color{R: 0.8, G: 0.6, B: 1.0, A: 0.5}},
+		{"src alpha saturated", same(BlendFactorSrcAlphaSaturated, BlendFactorZero, BlendOperationAdd), Color{R: 0.4, G: 0.2, B: 0.1, A: 0.5}},
+		{"constant", same(BlendFactorConstant, BlendFactorOneMinusConstant, BlendOperationAdd), Color{R: 0.5, G: 0.55, B: 0.2, A: 0.4375}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.Apply(src, dst, constant); !colorsClose(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorTargetState_Apply(t *testing.T) {
+	src := Color{R: 1, G: 1, B: 1, A: 1}
+	dst := Color{R: 0.25, G: 0.5, B: 0.75, A: 0}
+	blend := BlendStateAlpha()
+
+	target := ColorTargetState{Format: TextureFormatRGBA8Unorm, Blend: &blend, WriteMask: ColorWriteMaskRed | ColorWriteMaskAlpha}
+	want := Color{R: 1, G: 0.5, B: 0.75, A: 1}
+	if got := target.Apply(src, dst, Color{}); !colorsClose(got, want) {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+
+	target = ColorTargetState{Format: TextureFormatRGBA8Unorm, WriteMask: ColorWriteMaskNone}
+	if got := target.Apply(src, dst, Color{}); got != dst {
+		t.Errorf("Apply() with empty write mask = %+v, want %+v", got, dst)
+	}
+}
@@ -0,0 +1,145 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Compute dispatch errors.
+var (
+	// ErrWorkgroupSize means a workgroup size exceeds MaxComputeWorkgroupSizeX/Y/Z
+	// or MaxComputeInvocationsPerWorkgroup.
+	ErrWorkgroupSize = errors.New("invalid workgroup size")
+	// ErrWorkgroupStorage means workgroup storage exceeds MaxComputeWorkgroupStorageSize.
+	ErrWorkgroupStorage = errors.New("workgroup storage exceeds limit")
+)
+
+// ComputePassDescriptor describes a compute pass.
+type ComputePassDescriptor struct {
+	// Label is an optional debug label.
+	Label string
+	// TimestampWrites are the timestamps to write at the beginning and end
+	// of the pass (nil if none).
+	TimestampWrites *PassTimestampWrites
+}
+
+// Validate checks the timestamp writes, if any, as PassTimestampWrites.Validate
+// does without a query set size.
+func (d *ComputePassDescriptor) Validate() error {
+	if d.TimestampWrites == nil {
+		return nil
+	}
+	return d.TimestampWrites.Validate(QuerySetIndexUndefined)
+}
+
+// WorkgroupSize is the @workgroup_size of a compute shader. Components of
+// 0 are read as 1, as WGSL defaults omitted y and z.
+type WorkgroupSize [3]uint32
+
+// Normalized returns s with 0 components replaced by 1.
+func (s WorkgroupSize) Normalized() WorkgroupSize {
+	for i := range s {
+		s[i] = max(s[i], 1)
+	}
+	return s
+}
+
+// Invocations returns the number of invocations per workgroup.
+func (s WorkgroupSize) Invocations() uint64 {
+	n := s.Normalized()
+	return uint64(n[0]) * uint64(n[1]) * uint64(n[2])
+}
+
+// Validate checks s against the per-dimension and per-workgroup
+// invocation limits.
+func (s WorkgroupSize) Validate(limits Limits) error {
+	n := s.Normalized()
+	maxSize := [3]uint32{limits.MaxComputeWorkgroupSizeX, limits.MaxComputeWorkgroupSizeY, limits.MaxComputeWorkgroupSizeZ}
+	for i := range n {
+		if n[i] > maxSize[i] {
+			return fmt.Errorf("gputypes: %w: %c = %d > %d", ErrWorkgroupSize, "xyz"[i], n[i], maxSize[i])
+		}
+	}
+	if inv := s.Invocations(); inv > uint64(limits.MaxComputeInvocationsPerWorkgroup) {
+		return fmt.Errorf("gputypes: %w: %d invocations > %d", ErrWorkgroupSize, inv, limits.MaxComputeInvocationsPerWorkgroup)
+	}
+	return nil
+}
+
+// ComputeWorkload describes a compute problem to plan dispatches for.
+type ComputeWorkload struct {
+	// Size is the number of invocations needed in each dimension; a 1D
+	// problem of n items is {n, 1, 1}. A 0 component means no work.
+	Size [3]uint32
+	// WorkgroupSize is the shader's @workgroup_size.
+	WorkgroupSize WorkgroupSize
+	// WorkgroupStorage is the workgroup address space memory the shader
+	// uses, in bytes.
+	WorkgroupStorage uint32
+}
+
+// Dispatch is one dispatchWorkgroups call of a DispatchPlan.
+type Dispatch struct {
+	// Offset is the workgroup id of the dispatch's first workgroup in the
+	// whole problem. The shader must add it to workgroup_id, for example
+	// from a uniform, to find its place in the problem.
+	Offset [3]uint32
+	// Workgroups is the workgroup count per dimension to dispatch.
+	Workgroups [3]uint32
+}
+
+// DispatchPlan is the dispatches that cover a ComputeWorkload.
+type DispatchPlan struct {
+	// Workgroups is the total workgroup count per dimension.
+	Workgroups [3]uint32
+	// Dispatches cover Workgroups without overlap, X fastest. It is empty
+	// if the workload has no work.
+	Dispatches []Dispatch
+}
+
+// Plan validates the workload against limits and computes the workgroup
+// counts needed to cover Size. Dimensions needing more than
+// MaxComputeWorkgroupsPerDimension workgroups are split into several
+// dispatches with base offsets. Invocations beyond Size in the last
+// workgroup of each dimension must be skipped by the shader.
+func (w *ComputeWorkload) Plan(limits Limits) (DispatchPlan, error) {
+	if err := w.WorkgroupSize.Validate(limits); err != nil {
+		return DispatchPlan{}, err
+	}
+	if w.WorkgroupStorage > limits.MaxComputeWorkgroupStorageSize {
+		return DispatchPlan{}, fmt.Errorf("gputypes: %w: %d > %d bytes", ErrWorkgroupStorage, w.WorkgroupStorage, limits.MaxComputeWorkgroupStorageSize)
+	}
+	if limits.MaxComputeWorkgroupsPerDimension == 0 {
+		return DispatchPlan{}, fmt.Errorf("gputypes: MaxComputeWorkgroupsPerDimension is 0")
+	}
+
+	var plan DispatchPlan
+	size := w.WorkgroupSize.Normalized()
+	var chunks [3][]uint32 // workgroup offsets of the dispatches along each dimension
+	for i := range 3 {
+		n := (uint64(w.Size[i]) + uint64(size[i]) - 1) / uint64(size[i])
+		plan.Workgroups[i] = uint32(n)
+		for off := uint64(0); off < n; off += uint64(limits.MaxComputeWorkgroupsPerDimension) {
+			chunks[i] = append(chunks[i], uint32(off))
+		}
+	}
+
+	if slices.Contains(plan.Workgroups[:], 0) {
+		return plan, nil
+	}
+
+	step := limits.MaxComputeWorkgroupsPerDimension
+	for _, z := range chunks[2] {
+		for _, y := range chunks[1] {
+			for _, x := range chunks[0] {
+				d := Dispatch{Offset: [3]uint32{x, y, z}}
+				for i := range 3 {
+					d.Workgroups[i] = min(step, plan.Workgroups[i]-d.Offset[i])
+				}
+				plan.Dispatches = append(plan.Dispatches, d)
+			}
+		}
+	}
+	return plan, nil
+}
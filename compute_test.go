@@ -0,0 +1,99 @@
+package gputypes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorkgroupSize_Validate(t *testing.T) {
+	limits := DefaultLimits()
+	tests := []struct {
+		size WorkgroupSize
+		want error
+	}{
+		{WorkgroupSize{64}, nil},
+		{WorkgroupSize{16, 16}, nil},
+		{WorkgroupSize{4, 4, 16}, nil},
+		{WorkgroupSize{257}, ErrWorkgroupSize},
+		{WorkgroupSize{1, 1, 65}, ErrWorkgroupSize},
+		{WorkgroupSize{32, 16}, ErrWorkgroupSize},
+	}
+	for _, tt := range tests {
+		err := tt.size.Validate(limits)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%v.Validate() = %v, want %v", tt.size, err, tt.want)
+		}
+	}
+	if got := (WorkgroupSize{8}).Invocations(); got != 8 {
+		t.Errorf("WorkgroupSize{8}.Invocations() = %d, want 8", got)
+	}
+}
+
+func TestComputeWorkload_Plan(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxComputeWorkgroupsPerDimension = 100
+
+	w := ComputeWorkload{Size: [3]uint32{64*250 + 1, 3, 1}, WorkgroupSize: WorkgroupSize{64}}
+	plan, err := w.Plan(limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Workgroups != [3]uint32{251, 3, 1} {
+		t.Errorf("Workgroups = %v, want [251 3 1]", plan.Workgroups)
+	}
+	want := []Dispatch{
+		{Offset: [3]uint32{0, 0, 0}, Workgroups: [3]uint32{100, 3, 1}},
+		{Offset: [3]uint32{100, 0, 0}, Workgroups: [3]uint32{100, 3, 1}},
+		{Offset: [3]uint32{200, 0, 0}, Workgroups: [3]uint32{51, 3, 1}},
+	}
+	if len(plan.Dispatches) != len(want) {
+		t.Fatalf("Dispatches = %+v, want %+v", plan.Dispatches, want)
+	}
+	for i := range want {
+		if plan.Dispatches[i] != want[i] {
+			t.Errorf("dispatch %d = %+v, want %+v", i, plan.Dispatches[i], want[i])
+		}
+	}
+
+	// Splitting in two dimensions covers every workgroup exactly once.
+	w = ComputeWorkload{Size: [3]uint32{150, 250, 2}, WorkgroupSize: WorkgroupSize{1, 1, 1}}
+	plan, err = w.Plan(limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Dispatches) != 6 {
+		t.Errorf("got %d dispatches, want 6", len(plan.Dispatches))
+	}
+	var total uint64
+	for _, d := range plan.Dispatches {
+		total += uint64(d.Workgroups[0]) * uint64(d.Workgroups[1]) * uint64(d.Workgroups[2])
+	}
+	if total != 150*250*2 {
+		t.Errorf("dispatches cover %d workgroups, want %d", total, 150*250*2)
+	}
+
+	empty := ComputeWorkload{Size: [3]uint32{5, 0, 3}, WorkgroupSize: WorkgroupSize{1, 1, 1}}
+	if plan, err := empty.Plan(limits); err != nil || plan.Workgroups != [3]uint32{5, 0, 3} || len(plan.Dispatches) != 0 {
+		t.Errorf("empty workload plan = %+v, %v", plan, err)
+	}
+	if _, err := (&ComputeWorkload{Size: [3]uint32{1, 1, 1}, WorkgroupStorage: 16385}).Plan(limits); !errors.Is(err, ErrWorkgroupStorage) {
+		t.Errorf("storage error = %v, want ErrWorkgroupStorage", err)
+	}
+	if _, err := (&ComputeWorkload{Size: [3]uint32{1, 1, 1}, WorkgroupSize: WorkgroupSize{512}}).Plan(limits); !errors.Is(err, ErrWorkgroupSize) {
+		t.Errorf("size error = %v, want ErrWorkgroupSize", err)
+	}
+}
+
+func TestComputePassDescriptor_Validate(t *testing.T) {
+	if err := (&ComputePassDescriptor{}).Validate(); err != nil {
+		t.Errorf("zero descriptor Validate() = %v", err)
+	}
+	d := ComputePassDescriptor{TimestampWrites: &PassTimestampWrites{QuerySet: 1, EndOfPassWriteIndex: 1}}
+	if err := d.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	d.TimestampWrites.QuerySet = 0
+	if err := d.Validate(); !errors.Is(err, ErrTimestampWrites) {
+		t.Errorf("Validate() without query set = %v, want ErrTimestampWrites", err)
+	}
+}
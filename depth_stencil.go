@@ -0,0 +1,128 @@
+package gputypes
+
+// Compare reports whether value passes the comparison against reference,
+// as in "value < reference" for CompareFunctionLess.
+//
+// For depth tests value is the fragment depth and reference the stored
+// depth; for stencil tests value is the masked stencil reference and
+// reference the masked stored stencil value. CompareFunctionUndefined is
+// read as Always, as webgpu.h does for unused comparisons.
+func (f CompareFunction) Compare(value, reference float64) bool {
+	switch f {
+	case CompareFunctionNever:
+		return false
+	case CompareFunctionLess:
+		return value < reference
+	case CompareFunctionEqual:
+		return value == reference
+	case CompareFunctionLessEqual:
+		return value <= reference
+	case CompareFunctionGreater:
+		return value > reference
+	case CompareFunctionNotEqual:
+		return value != reference
+	case CompareFunctionGreaterEqual:
+		return value >= reference
+	default:
+		return true
+	}
+}
+
+// Apply returns the stencil value op produces from the stored value and
+// the stencil reference. Stencil values are 8 bits: increments and
+// decrements clamp or wrap at 0 and 255. StencilOperationUndefined is read
+// as Keep.
+func (op StencilOperation) Apply(stored, reference uint32) uint32 {
+	stored &= 0xFF
+	switch op {
+	case StencilOperationZero:
+		return 0
+	case StencilOperationReplace:
+		return reference & 0xFF
+	case StencilOperationInvert:
+		return ^stored & 0xFF
+	case StencilOperationIncrementClamp:
+		return min(stored+1, 0xFF)
+	case StencilOperationDecrementClamp:
+		return max(stored, 1) - 1
+	case StencilOperationIncrementWrap:
+		return (stored + 1) & 0xFF
+	case StencilOperationDecrementWrap:
+		return (stored - 1) & 0xFF
+	default:
+		return stored
+	}
+}
+
+// IsFrontFacing reports whether a triangle with the given winding in
+// framebuffer space is front-facing under f.
+func (f FrontFace) IsFrontFacing(counterClockwise bool) bool {
+	if f == FrontFaceCW {
+		return !counterClockwise
+	}
+	return counterClockwise
+}
+
+// StencilFace returns StencilFront for front-facing primitives and
+// StencilBack otherwise. Points and lines are always front-facing.
+func (s *DepthStencilState) StencilFace(frontFacing bool) StencilFaceState {
+	if frontFacing {
+		return s.StencilFront
+	}
+	return s.StencilBack
+}
+
+// DepthStencilResult is the outcome of the depth/stencil stage for one sample.
+type DepthStencilResult struct {
+	// Passed is true if the sample passed both tests and is kept.
+	Passed bool
+	// Depth is the depth value to store: the fragment depth if it passed and
+	// depth writes are enabled, else the stored depth.
+	Depth float64
+	// Stencil is the stencil value to store after the stencil operation and
+	// StencilWriteMask are applied.
+	Stencil uint32
+}
+
+// Evaluate runs the depth/stencil stage for one sample, as a reference for
+// software rasterizers and tests.
+//
+// The stencil test compares (reference & StencilReadMask) against
+// (storedStencil & StencilReadMask) with the face's Compare function. A
+// stencil failure applies FailOp; a depth failure applies DepthFailOp;
+// otherwise PassOp is applied and, if DepthWriteEnabled, the fragment depth
+// is written. Only StencilWriteMask bits of the stencil value change.
+//
+// Aspects the Format lacks always pass and are left unchanged.
+func (s *DepthStencilState) Evaluate(frontFacing bool, fragmentDepth, storedDepth float64, storedStencil, reference uint32) DepthStencilResult {
+	out := DepthStencilResult{Depth: storedDepth, Stencil: storedStencil}
+	face := s.StencilFace(frontFacing)
+	hasStencil := s.Format.HasStencil()
+
+	writeStencil := func(op StencilOperation) {
+		if hasStencil {
+			v := op.Apply(storedStencil, reference)
+			out.Stencil = storedStencil&^s.StencilWriteMask | v&s.StencilWriteMask
+		}
+	}
+
+	if hasStencil {
+		ref := float64(reference & s.StencilReadMask & 0xFF)
+		stored := float64(storedStencil & s.StencilReadMask & 0xFF)
+		if !face.Compare.Compare(ref, stored) {
+			writeStencil(face.FailOp)
+			return out
+		}
+	}
+	if s.Format.HasDepth() && !s.DepthCompare.Compare(fragmentDepth, storedDepth) {
+		writeStencil(face.DepthFailOp)
+		return out
+	}
+
+	writeStencil(face.PassOp)
+	if s.Format.HasDepth() && s.DepthWriteEnabled {
+		out.Depth = fragmentDepth
+	}
+	out.Passed = true
+	return out
+}
@@ -0,0 +1,114 @@
+package gputypes
+
+import "testing"
+
+func TestCompareFunction_Compare(t *testing.T) {
+	tests := []struct {
+		f                    CompareFunction
+		less, equal, greater bool
+	}{
+		{CompareFunctionUndefined, true, true, true},
+		{CompareFunctionNever, false, false, false},
+		{CompareFunctionLess, true, false, false},
+		{CompareFunctionEqual, false, true, false},
+		{CompareFunctionLessEqual, true, true, false},
+		{CompareFunctionGreater, false, false, true},
+		{CompareFunctionNotEqual, true, false, true},
+		{CompareFunctionGreaterEqual, false, true, true},
+		{CompareFunctionAlways, true, true, true},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Compare(0.25, 0.5); got != tt.less {
+			t.Errorf("%s.Compare(0.25, 0.5) = %v, want %v", tt.f, got, tt.less)
+		}
+		if got := tt.f.Compare(0.5, 0.5); got != tt.equal {
+			t.Errorf("%s.Compare(0.5, 0.5) = %v, want %v", tt.f, got, tt.equal)
+		}
+		if got := tt.f.Compare(0.75, 0.5); got != tt.greater {
+			t.Errorf("%s.Compare(0.75, 0.5) = %v, want %v", tt.f, got, tt.greater)
+		}
+	}
+}
+
+func TestStencilOperation_Apply(t *testing.T) {
+	tests := []struct {
+		op     StencilOperation
+		stored uint32
+		want   uint32
+	}{
+		{StencilOperationUndefined, 7, 7},
+		{StencilOperationKeep, 7, 7},
+		{StencilOperationZero, 7, 0},
+		{StencilOperationReplace, 7, 0x42},
+		{StencilOperationInvert, 0x0F, 0xF0},
+		{StencilOperationIncrementClamp, 7, 8},
+		{StencilOperationIncrementClamp, 255, 255},
+		{StencilOperationDecrementClamp, 7, 6},
+		{StencilOperationDecrementClamp, 0, 0},
+		{StencilOperationIncrementWrap, 255, 0},
+		{StencilOperationDecrementWrap, 0, 255},
+	}
+	for _, tt := range tests {
+		if got := tt.op.Apply(tt.stored, 0x42); got != tt.want {
+			t.Errorf("%d.Apply(%d, 0x42) = %d, want %d", tt.op, tt.stored, got, tt.want)
+		}
+	}
+}
+
+func TestFrontFace_IsFrontFacing(t *testing.T) {
+	if !FrontFaceCCW.IsFrontFacing(true) || FrontFaceCCW.IsFrontFacing(false) {
+		t.Error("FrontFaceCCW facing is wrong")
+	}
+	if FrontFaceCW.IsFrontFacing(true) || !FrontFaceCW.IsFrontFacing(false) {
+		t.Error("FrontFaceCW facing is wrong")
+	}
+}
+
+func TestDepthStencilState_Evaluate(t *testing.T) {
+	// Stencil-masked depth test: front faces increment where stencil == 1,
+	// back faces always pass and zero the low bit.
+	state := DefaultDepthStencilState(TextureFormatDepth24PlusStencil8)
+	state.StencilFront = StencilFaceState{
+		Compare:     CompareFunctionEqual,
+		FailOp:      StencilOperationZero,
+		DepthFailOp: StencilOperationInvert,
+		PassOp:      StencilOperationIncrementClamp,
+	}
+	state.StencilBack = StencilFaceState{Compare: CompareFunctionAlways, PassOp: StencilOperationZero}
+	state.StencilReadMask = 0x0F
+	state.StencilWriteMask = 0x01
+
+	tests := []struct {
+		name          string
+		state         DepthStencilState
+		front         bool
+		depth, stored float64
+		stencil       uint32
+		want          DepthStencilResult
+	}{
+		{"pass", state, true, 0.25, 0.5, 0xF1, DepthStencilResult{Passed: true, Depth: 0.25, Stencil: 0xF0}},
+		{"stencil fail", state, true, 0.25, 0.5, 0x02, DepthStencilResult{Depth: 0.5, Stencil: 0x02}},
+		{"read mask ignores high bits", state, true, 0.75, 0.5, 0x31, DepthStencilResult{Depth: 0.5, Stencil: 0x30}},
+		{"back face", state, false, 0.25, 0.5, 0x03, DepthStencilResult{Passed: true, Depth: 0.25, Stencil: 0x02}},
+		{"depth only", DefaultDepthStencilState(TextureFormatDepth32Float), true, 0.25, 0.5, 9,
+			DepthStencilResult{Passed: true, Depth: 0.25, Stencil: 9}},
+		{"depth fail, no write", DepthStencilState{Format: TextureFormatDepth16Unorm, DepthCompare: CompareFunctionLess},
+			true, 0.75, 0.5, 0, DepthStencilResult{Depth: 0.5}},
+		{"no depth write", DepthStencilState{Format: TextureFormatDepth16Unorm, DepthCompare: CompareFunctionAlways},
+			true, 0.75, 0.5, 0, DepthStencilResult{Passed: true, Depth: 0.5}},
+		{"stencil only ignores depth", DepthStencilState{Format: TextureFormatStencil8, StencilWriteMask: 0xFF,
+			StencilFront: StencilFaceState{PassOp: StencilOperationReplace}}, true, 1, 0, 3,
+			DepthStencilResult{Passed: true, Depth: 0, Stencil: 0x42}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref := uint32(1)
+			if tt.state.Format == TextureFormatStencil8 {
+				ref = 0x42
+			}
+			if got := tt.state.Evaluate(tt.front, tt.depth, tt.stored, tt.stencil, ref); got != tt.want {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -28,12 +28,14 @@
 //
 // Sampler types: AddressMode, FilterMode, CompareFunction, SamplerDescriptor, etc.
 //
-// Render types: BlendState, BlendFactor, BlendOperation, PrimitiveTopology, etc.
+// Render types: RenderPipelineDescriptor, BlendState, BlendFactor, PrimitiveTopology, etc.
 //
-// Shader types: ShaderStage, ShaderModuleDescriptor, etc.
+// Shader types: ShaderStage, ShaderModuleDescriptor, ReflectWGSL, etc.
 //
 // Vertex types: VertexFormat, VertexStepMode, VertexAttribute, etc.
 //
+// Query types: QueryType, QuerySetDescriptor, PipelineStatisticName, etc.
+//
 // Binding types: BindGroupLayoutEntry, BufferBindingLayout, TextureBindingLayout, etc.
 //
 // Adapter types: DeviceType, AdapterInfo, PowerPreference, Backend, etc.
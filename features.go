@@ -47,6 +47,14 @@ const (
 	FeatureSubgroupOperations
 	// FeatureSubgroupBarrier enables subgroup barriers in shaders.
 	FeatureSubgroupBarrier
+	// FeatureBindingArrays enables binding arrays (BindGroupLayoutEntry.Count > 0).
+	FeatureBindingArrays
+	// FeatureNonUniformIndexing enables indexing binding arrays with
+	// non-uniform values in shaders.
+	FeatureNonUniformIndexing
+	// FeaturePartiallyBoundBindingArrays allows bind groups to bind fewer
+	// array elements than the layout's Count.
+	FeaturePartiallyBoundBindingArrays
 )
 
 // String returns the feature name.
@@ -92,6 +100,12 @@ func (f Feature) String() string {
 		return "SubgroupOperations"
 	case FeatureSubgroupBarrier:
 		return "SubgroupBarrier"
+	case FeatureBindingArrays:
+		return "BindingArrays"
+	case FeatureNonUniformIndexing:
+		return "NonUniformIndexing"
+	case FeaturePartiallyBoundBindingArrays:
+		return "PartiallyBoundBindingArrays"
 	default:
 		return "Unknown"
 	}
@@ -0,0 +1,202 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// IsStrip returns true for strip topologies, which support primitive restart.
+func (t PrimitiveTopology) IsStrip() bool {
+	return t == PrimitiveTopologyLineStrip || t == PrimitiveTopologyTriangleStrip
+}
+
+// ListTopology returns the list topology that draws the same primitive type:
+// LineList for LineStrip, TriangleList for TriangleStrip. List topologies
+// are returned unchanged.
+func (t PrimitiveTopology) ListTopology() PrimitiveTopology {
+	switch t {
+	case PrimitiveTopologyLineStrip:
+		return PrimitiveTopologyLineList
+	case PrimitiveTopologyTriangleStrip:
+		return PrimitiveTopologyTriangleList
+	default:
+		return t
+	}
+}
+
+// PrimitiveRestartValue returns the index value that ends the current strip
+// and starts a new one: 0xFFFF for Uint16 and 0xFFFFFFFF for Uint32.
+//
+// Returns 0 for undefined or unknown formats.
+func (f IndexFormat) PrimitiveRestartValue() uint32 {
+	switch f {
+	case IndexFormatUint16:
+		return 0xFFFF
+	case IndexFormatUint32:
+		return 0xFFFFFFFF
+	default:
+		return 0
+	}
+}
+
+// PrimitiveRestartValue returns the primitive restart index implied by the
+// state: the restart value of StripIndexFormat for strip topologies.
+//
+// Returns false for list topologies or when StripIndexFormat is nil.
+func (s PrimitiveState) PrimitiveRestartValue() (uint32, bool) {
+	if !s.Topology.IsStrip() || s.StripIndexFormat == nil {
+		return 0, false
+	}
+	v := s.StripIndexFormat.PrimitiveRestartValue()
+	return v, v != 0
+}
+
+// ReadIndices decodes little-endian index data in format f.
+func (f IndexFormat) ReadIndices(data []byte) ([]uint32, error) {
+	size := int(f.Size())
+	if size == 0 {
+		return nil, fmt.Errorf("gputypes: invalid index format %s", f)
+	}
+	if len(data)%size != 0 {
+		return nil, fmt.Errorf("gputypes: index data length %d is not a multiple of %d", len(data), size)
+	}
+	indices := make([]uint32, len(data)/size)
+	for i := range indices {
+		indices[i] = readIndex(data[i*size:], f)
+	}
+	return indices, nil
+}
+
+// AppendIndices appends indices to dst as little-endian data in format f.
+// It fails if an index does not fit the format.
+func (f IndexFormat) AppendIndices(dst []byte, indices []uint32) ([]byte, error) {
+	switch f {
+	case IndexFormatUint16:
+		for i, v := range indices {
+			if v > 0xFFFF {
+				return dst, fmt.Errorf("gputypes: index %d value %d does not fit %s", i, v, f)
+			}
+			dst = binary.LittleEndian.AppendUint16(dst, uint16(v))
+		}
+	case IndexFormatUint32:
+		for _, v := range indices {
+			dst = binary.LittleEndian.AppendUint32(dst, v)
+		}
+	default:
+		return dst, fmt.Errorf("gputypes: invalid index format %s", f)
+	}
+	return dst, nil
+}
+
+// IndexRange returns the smallest and largest vertex index referenced by
+// data. When restart is true, primitive restart values are skipped.
+//
+// ok is false if data references no vertices.
+func (f IndexFormat) IndexRange(data []byte, restart bool) (lo, hi uint32, ok bool, err error) {
+	indices, err := f.ReadIndices(data)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	sentinel := f.PrimitiveRestartValue()
+	for _, v := range indices {
+		if restart && v == sentinel {
+			continue
+		}
+		if !ok || v < lo {
+			lo = v
+		}
+		if !ok || v > hi {
+			hi = v
+		}
+		ok = true
+	}
+	return lo, hi, ok, nil
+}
+
+// StripToList converts strip index data in format f to the equivalent list
+// data (see PrimitiveTopology.ListTopology) in the same format.
+//
+// Primitive restart values split strips. Odd triangles of a triangle strip
+// are emitted with their first two vertices swapped, so every triangle keeps
+// the winding the strip gives it; degenerate triangles (two equal indices),
+// often used to stitch strips together, are dropped.
+func (f IndexFormat) StripToList(data []byte, topology PrimitiveTopology) ([]byte, error) {
+	if !topology.IsStrip() {
+		return nil, fmt.Errorf("gputypes: %s is not a strip topology", topology)
+	}
+	indices, err := f.ReadIndices(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sentinel := f.PrimitiveRestartValue()
+	var list []uint32
+	start := 0
+	for i := 0; i <= len(indices); i++ {
+		if i < len(indices) && indices[i] != sentinel {
+			continue
+		}
+		strip := indices[start:i]
+		start = i + 1
+
+		if topology == PrimitiveTopologyLineStrip {
+			for j := 1; j < len(strip); j++ {
+				list = append(list, strip[j-1], strip[j])
+			}
+			continue
+		}
+		for j := 2; j < len(strip); j++ {
+			a, b, c := strip[j-2], strip[j-1], strip[j]
+			if a == b || b == c || a == c {
+				continue
+			}
+			if j%2 == 1 {
+				a, b = b, a
+			}
+			list = append(list, a, b, c)
+		}
+	}
+	return f.AppendIndices(make([]byte, 0, len(list)*int(f.Size())), list)
+}
+
+// Narrow converts Uint32 index data to Uint16 when every index fits.
+//
+// When restart is true, 0xFFFFFFFF restart values become 0xFFFF and other
+// indices must be below 0xFFFF; otherwise every index must be at most 0xFFFF.
+// If the data cannot be narrowed, or f is not Uint32, f and data are
+// returned unchanged.
+func (f IndexFormat) Narrow(data []byte, restart bool) (IndexFormat, []byte, error) {
+	if f != IndexFormatUint32 {
+		return f, data, nil
+	}
+	indices, err := f.ReadIndices(data)
+	if err != nil {
+		return f, data, err
+	}
+
+	limit := uint32(0xFFFF)
+	if restart {
+		limit = 0xFFFE
+	}
+	for i, v := range indices {
+		switch {
+		case restart && v == 0xFFFFFFFF:
+			indices[i] = 0xFFFF
+		case v > limit:
+			return f, data, nil
+		}
+	}
+	out, err := IndexFormatUint16.AppendIndices(make([]byte, 0, len(indices)*2), indices)
+	if err != nil {
+		return f, data, err
+	}
+	return IndexFormatUint16, out, nil
+}
+
+// readIndex loads one little-endian index of format f from the start of b.
+func readIndex(b []byte, f IndexFormat) uint32 {
+	if f == IndexFormatUint16 {
+		return uint32(binary.LittleEndian.Uint16(b))
+	}
+	return binary.LittleEndian.Uint32(b)
+}
@@ -0,0 +1,146 @@
+package gputypes
+
+import (
+	"slices"
+	"testing"
+)
+
+func indexData(t *testing.T, f IndexFormat, indices ...uint32) []byte {
+	t.Helper()
+	data, err := f.AppendIndices(nil, indices)
+	if err != nil {
+		t.Fatalf("AppendIndices() error = %v", err)
+	}
+	return data
+}
+
+func TestIndexFormat_StripToList(t *testing.T) {
+	const r16 = 0xFFFF
+	tests := []struct {
+		name     string
+		format   IndexFormat
+		topology PrimitiveTopology
+		strip    []uint32
+		want     []uint32
+	}{
+		{
+			name:   "triangle strip keeps winding",
+			format: IndexFormatUint16, topology: PrimitiveTopologyTriangleStrip,
+			strip: []uint32{0, 1, 2, 3, 4},
+			want:  []uint32{0, 1, 2, 2, 1, 3, 2, 3, 4},
+		},
+		{
+			name:   "restart splits strips",
+			format: IndexFormatUint16, topology: PrimitiveTopologyTriangleStrip,
+			strip: []uint32{0, 1, 2, 3, r16, 4, 5, 6},
+			want:  []uint32{0, 1, 2, 2, 1, 3, 4, 5, 6},
+		},
+		{
+			name:   "degenerate triangles dropped",
+			format: IndexFormatUint32, topology: PrimitiveTopologyTriangleStrip,
+			strip: []uint32{0, 1, 2, 2, 5, 5, 6, 7},
+			want:  []uint32{0, 1, 2, 6, 5, 7},
+		},
+		{
+			name:   "line strip",
+			format: IndexFormatUint32, topology: PrimitiveTopologyLineStrip,
+			strip: []uint32{0, 1, 2, 0xFFFFFFFF, 3, 4},
+			want:  []uint32{0, 1, 1, 2, 3, 4},
+		},
+		{
+			name:   "short strip",
+			format: IndexFormatUint16, topology: PrimitiveTopologyTriangleStrip,
+			strip: []uint32{0, 1},
+			want:  []uint32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.format.StripToList(indexData(t, tt.format, tt.strip...), tt.topology)
+			if err != nil {
+				t.Fatalf("StripToList() error = %v", err)
+			}
+			got, err := tt.format.ReadIndices(out)
+			if err != nil {
+				t.Fatalf("ReadIndices() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("StripToList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := IndexFormatUint16.StripToList(nil, PrimitiveTopologyTriangleList); err == nil {
+		t.Error("StripToList() accepted a list topology")
+	}
+}
+
+func TestIndexFormat_IndexRange(t *testing.T) {
+	data := indexData(t, IndexFormatUint16, 7, 3, 0xFFFF, 9)
+
+	lo, hi, ok, err := IndexFormatUint16.IndexRange(data, true)
+	if err != nil || !ok || lo != 3 || hi != 9 {
+		t.Errorf("IndexRange(restart) = %d, %d, %v, %v; want 3, 9, true, nil", lo, hi, ok, err)
+	}
+	lo, hi, ok, err = IndexFormatUint16.IndexRange(data, false)
+	if err != nil || !ok || lo != 3 || hi != 0xFFFF {
+		t.Errorf("IndexRange(no restart) = %d, %d, %v, %v; want 3, 65535, true, nil", lo, hi, ok, err)
+	}
+	if _, _, ok, _ := IndexFormatUint16.IndexRange(indexData(t, IndexFormatUint16, 0xFFFF), true); ok {
+		t.Error("IndexRange() of only restart values reported ok")
+	}
+	if _, _, _, err := IndexFormatUint32.IndexRange(make([]byte, 6), false); err == nil {
+		t.Error("IndexRange() accepted a partial index")
+	}
+}
+
+func TestIndexFormat_Narrow(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []uint32
+		restart bool
+		want    IndexFormat
+		wantIdx []uint32
+	}{
+		{"fits", []uint32{0, 1, 65535}, false, IndexFormatUint16, []uint32{0, 1, 65535}},
+		{"too large", []uint32{0, 65536}, false, IndexFormatUint32, []uint32{0, 65536}},
+		{"restart mapped", []uint32{0, 0xFFFFFFFF, 2}, true, IndexFormatUint16, []uint32{0, 0xFFFF, 2}},
+		{"collides with restart", []uint32{0, 0xFFFF}, true, IndexFormatUint32, []uint32{0, 0xFFFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, out, err := IndexFormatUint32.Narrow(indexData(t, IndexFormatUint32, tt.indices...), tt.restart)
+			if err != nil {
+				t.Fatalf("Narrow() error = %v", err)
+			}
+			if format != tt.want {
+				t.Errorf("Narrow() format = %s, want %s", format, tt.want)
+			}
+			got, _ := format.ReadIndices(out)
+			if !slices.Equal(got, tt.wantIdx) {
+				t.Errorf("Narrow() indices = %v, want %v", got, tt.wantIdx)
+			}
+		})
+	}
+}
+
+func TestPrimitiveState_PrimitiveRestartValue(t *testing.T) {
+	u16, u32 := IndexFormatUint16, IndexFormatUint32
+	tests := []struct {
+		state PrimitiveState
+		want  uint32
+		ok    bool
+	}{
+		{PrimitiveState{}, 0, false},
+		{PrimitiveState{Topology: PrimitiveTopologyTriangleStrip}, 0, false},
+		{PrimitiveState{Topology: PrimitiveTopologyTriangleList, StripIndexFormat: &u16}, 0, false},
+		{PrimitiveState{Topology: PrimitiveTopologyLineStrip, StripIndexFormat: &u16}, 0xFFFF, true},
+		{PrimitiveState{Topology: PrimitiveTopologyTriangleStrip, StripIndexFormat: &u32}, 0xFFFFFFFF, true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.state.PrimitiveRestartValue()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s.PrimitiveRestartValue() = %#x, %v; want %#x, %v", tt.state.Topology, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Sizes of indirect command arguments in bytes. Multi-draw commands read
+// consecutive arguments at these strides.
+const (
+	// DrawIndirectArgsSize is the size of DrawIndirectArgs.
+	DrawIndirectArgsSize = 16
+	// DrawIndexedIndirectArgsSize is the size of DrawIndexedIndirectArgs.
+	DrawIndexedIndirectArgsSize = 20
+	// DispatchIndirectArgsSize is the size of DispatchIndirectArgs.
+	DispatchIndirectArgsSize = 12
+	// IndirectCountSize is the size of the uint32 draw count read from a
+	// multi-draw count buffer.
+	IndirectCountSize = 4
+	// IndirectOffsetAlignment is the required alignment of indirect buffer
+	// and count buffer offsets.
+	IndirectOffsetAlignment = 4
+)
+
+// Indirect command errors.
+var (
+	// ErrIndirectFirstInstance means an indirect draw has a non-zero
+	// firstInstance without FeatureIndirectFirstInstance.
+	ErrIndirectFirstInstance = errors.New("indirect firstInstance requires IndirectFirstInstance")
+	// ErrIndirectOffset means an indirect or count buffer offset is not a
+	// multiple of IndirectOffsetAlignment.
+	ErrIndirectOffset = errors.New("indirect offset is not 4-byte aligned")
+	// ErrIndirectBufferSize means the arguments or draw count extend past
+	// the end of the buffer.
+	ErrIndirectBufferSize = errors.New("indirect arguments exceed buffer size")
+	// ErrWorkgroupCount means an indirect dispatch exceeds MaxComputeWorkgroupsPerDimension.
+	ErrWorkgroupCount = errors.New("workgroup count exceeds limit")
+)
+
+// DrawIndirectArgs are the arguments of drawIndirect, as stored in an
+// indirect buffer.
+type DrawIndirectArgs struct {
+	VertexCount   uint32
+	InstanceCount uint32
+	FirstVertex   uint32
+	FirstInstance uint32
+}
+
+// Encode writes the arguments to dst in little-endian order.
+func (a DrawIndirectArgs) Encode(dst []byte) error {
+	if len(dst) < DrawIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DrawIndirectArgs, need %d", ErrIndirectBufferSize, len(dst), DrawIndirectArgsSize)
+	}
+	putUint32s(dst, a.VertexCount, a.InstanceCount, a.FirstVertex, a.FirstInstance)
+	return nil
+}
+
+// Decode reads the arguments from src.
+func (a *DrawIndirectArgs) Decode(src []byte) error {
+	if len(src) < DrawIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DrawIndirectArgs, need %d", ErrIndirectBufferSize, len(src), DrawIndirectArgsSize)
+	}
+	a.VertexCount, a.InstanceCount, a.FirstVertex, a.FirstInstance = le32(src, 0), le32(src, 1), le32(src, 2), le32(src, 3)
+	return nil
+}
+
+// Validate checks that FirstInstance is 0 unless FeatureIndirectFirstInstance
+// is enabled. Without the feature the GPU skips such draws.
+func (a DrawIndirectArgs) Validate(features Features) error {
+	return checkFirstInstance(a.FirstInstance, features)
+}
+
+// DrawIndexedIndirectArgs are the arguments of drawIndexedIndirect, as
+// stored in an indirect buffer.
+type DrawIndexedIndirectArgs struct {
+	IndexCount    uint32
+	InstanceCount uint32
+	FirstIndex    uint32
+	BaseVertex    int32
+	FirstInstance uint32
+}
+
+// Encode writes the arguments to dst in little-endian order.
+func (a DrawIndexedIndirectArgs) Encode(dst []byte) error {
+	if len(dst) < DrawIndexedIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DrawIndexedIndirectArgs, need %d", ErrIndirectBufferSize, len(dst), DrawIndexedIndirectArgsSize)
+	}
+	putUint32s(dst, a.IndexCount, a.InstanceCount, a.FirstIndex, uint32(a.BaseVertex), a.FirstInstance)
+	return nil
+}
+
+// Decode reads the arguments from src.
+func (a *DrawIndexedIndirectArgs) Decode(src []byte) error {
+	if len(src) < DrawIndexedIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DrawIndexedIndirectArgs, need %d", ErrIndirectBufferSize, len(src), DrawIndexedIndirectArgsSize)
+	}
+	a.IndexCount, a.InstanceCount, a.FirstIndex = le32(src, 0), le32(src, 1), le32(src, 2)
+	a.BaseVertex, a.FirstInstance = int32(le32(src, 3)), le32(src, 4)
+	return nil
+}
+
+// Validate checks that FirstInstance is 0 unless FeatureIndirectFirstInstance
+// is enabled. Without the feature the GPU skips such draws.
+func (a DrawIndexedIndirectArgs) Validate(features Features) error {
+	return checkFirstInstance(a.FirstInstance, features)
+}
+
+// DispatchIndirectArgs are the arguments of dispatchWorkgroupsIndirect, as
+// stored in an indirect buffer.
+type DispatchIndirectArgs struct {
+	WorkgroupCountX uint32
+	WorkgroupCountY uint32
+	WorkgroupCountZ uint32
+}
+
+// Encode writes the arguments to dst in little-endian order.
+func (a DispatchIndirectArgs) Encode(dst []byte) error {
+	if len(dst) < DispatchIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DispatchIndirectArgs, need %d", ErrIndirectBufferSize, len(dst), DispatchIndirectArgsSize)
+	}
+	putUint32s(dst, a.WorkgroupCountX, a.WorkgroupCountY, a.WorkgroupCountZ)
+	return nil
+}
+
+// Decode reads the arguments from src.
+func (a *DispatchIndirectArgs) Decode(src []byte) error {
+	if len(src) < DispatchIndirectArgsSize {
+		return fmt.Errorf("gputypes: %w: %d bytes for DispatchIndirectArgs, need %d", ErrIndirectBufferSize, len(src), DispatchIndirectArgsSize)
+	}
+	a.WorkgroupCountX, a.WorkgroupCountY, a.WorkgroupCountZ = le32(src, 0), le32(src, 1), le32(src, 2)
+	return nil
+}
+
+// Validate checks the workgroup counts against MaxComputeWorkgroupsPerDimension.
+// The GPU skips dispatches that exceed it.
+func (a DispatchIndirectArgs) Validate(limits Limits) error {
+	for i, n := range [3]uint32{a.WorkgroupCountX, a.WorkgroupCountY, a.WorkgroupCountZ} {
+		if n > limits.MaxComputeWorkgroupsPerDimension {
+			return fmt.Errorf("gputypes: %w: %c = %d > %d", ErrWorkgroupCount, "xyz"[i], n, limits.MaxComputeWorkgroupsPerDimension)
+		}
+	}
+	return nil
+}
+
+// IndirectDraw describes the buffer ranges of an indirect draw call:
+// drawIndirect, drawIndexedIndirect, or their multi-draw variants.
+type IndirectDraw struct {
+	// Indexed selects DrawIndexedIndirectArgs instead of DrawIndirectArgs.
+	Indexed bool
+	// BufferSize is the size of the indirect buffer in bytes.
+	BufferSize uint64
+	// Offset is the byte offset of the first arguments in the indirect buffer.
+	Offset uint64
+	// DrawCount is the number of draws, or the maximum draw count with a
+	// count buffer. 0 is read as 1; more than one needs FeatureMultiDrawIndirect.
+	DrawCount uint32
+	// CountBufferSize is the size of the count buffer in bytes, or 0 if
+	// there is none. A count buffer needs FeatureMultiDrawIndirectCount.
+	CountBufferSize uint64
+	// CountOffset is the byte offset of the uint32 draw count in the count buffer.
+	CountOffset uint64
+}
+
+// Stride returns the size of one draw's arguments.
+func (d *IndirectDraw) Stride() uint64 {
+	if d.Indexed {
+		return DrawIndexedIndirectArgsSize
+	}
+	return DrawIndirectArgsSize
+}
+
+// Validate checks the offsets, buffer sizes and multi-draw features of the
+// draw call. Arguments of all DrawCount draws, packed at Stride, must fit
+// in the indirect buffer.
+func (d *IndirectDraw) Validate(features Features) error {
+	count := max(d.DrawCount, 1)
+	if d.CountBufferSize != 0 {
+		if !features.Contains(FeatureMultiDrawIndirectCount) {
+			return fmt.Errorf("gputypes: %w: count buffer needs %s", ErrMissingFeature, FeatureMultiDrawIndirectCount)
+		}
+		if err := ValidateIndirectRange(d.CountOffset, IndirectCountSize, d.CountBufferSize); err != nil {
+			return fmt.Errorf("%w (count buffer)", err)
+		}
+	} else if count > 1 && !features.Contains(FeatureMultiDrawIndirect) {
+		return fmt.Errorf("gputypes: %w: %d draws need %s", ErrMissingFeature, count, FeatureMultiDrawIndirect)
+	}
+	return ValidateIndirectRange(d.Offset, uint64(count)*d.Stride(), d.BufferSize)
+}
+
+// ValidateIndirectRange checks that size bytes at offset fit in a buffer
+// of bufferSize bytes and that offset is 4-byte aligned.
+func ValidateIndirectRange(offset, size, bufferSize uint64) error {
+	if offset%IndirectOffsetAlignment != 0 {
+		return fmt.Errorf("gputypes: %w: %d", ErrIndirectOffset, offset)
+	}
+	if offset > bufferSize || size > bufferSize-offset {
+		return fmt.Errorf("gputypes: %w: %d bytes at offset %d, buffer has %d", ErrIndirectBufferSize, size, offset, bufferSize)
+	}
+	return nil
+}
+
+// checkFirstInstance implements the Validate methods of the draw arguments.
+func checkFirstInstance(firstInstance uint32, features Features) error {
+	if firstInstance != 0 && !features.Contains(FeatureIndirectFirstInstance) {
+		return fmt.Errorf("gputypes: %w: firstInstance = %d", ErrIndirectFirstInstance, firstInstance)
+	}
+	return nil
+}
+
+// putUint32s writes values to dst as consecutive little-endian uint32s.
+func putUint32s(dst []byte, values ...uint32) {
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(dst[4*i:], v)
+	}
+}
+
+// le32 returns the i-th little-endian uint32 of src.
+func le32(src []byte, i int) uint32 {
+	return binary.LittleEndian.Uint32(src[4*i:])
+}
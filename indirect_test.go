@@ -0,0 +1,106 @@
+package gputypes
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestIndirectArgs_Encoding(t *testing.T) {
+	draw := DrawIndirectArgs{VertexCount: 3, InstanceCount: 2, FirstVertex: 0x0102, FirstInstance: 7}
+	buf := make([]byte, DrawIndirectArgsSize)
+	if err := draw.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{3, 0, 0, 0, 2, 0, 0, 0, 0x02, 0x01, 0, 0, 7, 0, 0, 0}
+	if !bytes.Equal(buf, want) {
+		t.Errorf("DrawIndirectArgs.Encode() = %v, want %v", buf, want)
+	}
+	var gotDraw DrawIndirectArgs
+	if err := gotDraw.Decode(buf); err != nil || gotDraw != draw {
+		t.Errorf("Decode() = %+v, %v", gotDraw, err)
+	}
+
+	indexed := DrawIndexedIndirectArgs{IndexCount: 36, InstanceCount: 1, FirstIndex: 6, BaseVertex: -4, FirstInstance: 1}
+	buf = make([]byte, DrawIndexedIndirectArgsSize)
+	if err := indexed.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[12:16], []byte{0xFC, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("BaseVertex bytes = %v, want -4 two's complement", buf[12:16])
+	}
+	var gotIndexed DrawIndexedIndirectArgs
+	if err := gotIndexed.Decode(buf); err != nil || gotIndexed != indexed {
+		t.Errorf("Decode() = %+v, %v", gotIndexed, err)
+	}
+
+	dispatch := DispatchIndirectArgs{WorkgroupCountX: 8, WorkgroupCountY: 4, WorkgroupCountZ: 1}
+	buf = make([]byte, DispatchIndirectArgsSize)
+	if err := dispatch.Encode(buf); err != nil {
+		t.Fatal(err)
+	}
+	var gotDispatch DispatchIndirectArgs
+	if err := gotDispatch.Decode(buf); err != nil || gotDispatch != dispatch {
+		t.Errorf("Decode() = %+v, %v", gotDispatch, err)
+	}
+
+	if err := draw.Encode(make([]byte, 15)); !errors.Is(err, ErrIndirectBufferSize) {
+		t.Errorf("short Encode error = %v", err)
+	}
+	if err := gotIndexed.Decode(make([]byte, 16)); !errors.Is(err, ErrIndirectBufferSize) {
+		t.Errorf("short Decode error = %v", err)
+	}
+}
+
+func TestIndirectArgs_Validate(t *testing.T) {
+	first := Features(FeatureIndirectFirstInstance)
+	if err := (DrawIndirectArgs{FirstInstance: 1}).Validate(0); !errors.Is(err, ErrIndirectFirstInstance) {
+		t.Errorf("firstInstance without feature error = %v", err)
+	}
+	if err := (DrawIndexedIndirectArgs{FirstInstance: 1}).Validate(first); err != nil {
+		t.Errorf("firstInstance with feature error = %v", err)
+	}
+	if err := (DrawIndexedIndirectArgs{InstanceCount: 5}).Validate(0); err != nil {
+		t.Errorf("zero firstInstance error = %v", err)
+	}
+
+	limits := DefaultLimits()
+	if err := (DispatchIndirectArgs{65535, 1, 1}).Validate(limits); err != nil {
+		t.Errorf("dispatch at limit error = %v", err)
+	}
+	if err := (DispatchIndirectArgs{1, 1, 65536}).Validate(limits); !errors.Is(err, ErrWorkgroupCount) {
+		t.Errorf("dispatch over limit error = %v", err)
+	}
+}
+
+func TestIndirectDraw_Validate(t *testing.T) {
+	multi := Features(FeatureMultiDrawIndirect)
+	count := Features(FeatureMultiDrawIndirectCount)
+	tests := []struct {
+		name     string
+		draw     IndirectDraw
+		features Features
+		want     error
+	}{
+		{"single", IndirectDraw{BufferSize: 16}, 0, nil},
+		{"indexed at end", IndirectDraw{Indexed: true, BufferSize: 40, Offset: 20}, 0, nil},
+		{"unaligned", IndirectDraw{BufferSize: 64, Offset: 2}, 0, ErrIndirectOffset},
+		{"past end", IndirectDraw{Indexed: true, BufferSize: 32, Offset: 16}, 0, ErrIndirectBufferSize},
+		{"offset past end", IndirectDraw{BufferSize: 16, Offset: 32}, 0, ErrIndirectBufferSize},
+		{"multi", IndirectDraw{BufferSize: 64, DrawCount: 4}, multi, nil},
+		{"multi without feature", IndirectDraw{BufferSize: 64, DrawCount: 4}, 0, ErrMissingFeature},
+		{"multi too large", IndirectDraw{BufferSize: 64, Offset: 4, DrawCount: 4}, multi, ErrIndirectBufferSize},
+		{"count buffer", IndirectDraw{BufferSize: 64, DrawCount: 4, CountBufferSize: 8, CountOffset: 4}, count, nil},
+		{"count buffer without feature", IndirectDraw{BufferSize: 64, DrawCount: 4, CountBufferSize: 8}, multi, ErrMissingFeature},
+		{"count offset unaligned", IndirectDraw{BufferSize: 64, CountBufferSize: 8, CountOffset: 1}, count, ErrIndirectOffset},
+		{"count past end", IndirectDraw{BufferSize: 64, CountBufferSize: 8, CountOffset: 8}, count, ErrIndirectBufferSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.draw.Validate(tt.features)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
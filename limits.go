@@ -71,6 +71,14 @@ type Limits struct {
 	MaxPushConstantSize uint32
 	// MaxNonSamplerBindings is the max non-sampler bindings.
 	MaxNonSamplerBindings uint32
+	// MaxBindingArrayElementsPerShaderStage is the max binding array elements,
+	// other than samplers, per shader stage (non-standard extension, 0 without
+	// FeatureBindingArrays).
+	MaxBindingArrayElementsPerShaderStage uint32
+	// MaxBindingArraySamplerElementsPerShaderStage is the max sampler binding
+	// array elements per shader stage (non-standard extension, 0 without
+	// FeatureBindingArrays).
+	MaxBindingArraySamplerElementsPerShaderStage uint32
 }
 
 // DefaultLimits returns the default WebGPU limits.
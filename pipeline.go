@@ -0,0 +1,68 @@
+package gputypes
+
+// PipelineLayoutAuto is the Layout value that requests an implicit pipeline
+// layout, derived from the shader bindings ("auto" in the WebGPU spec).
+//
+// It is the zero value, so a descriptor that leaves Layout unset uses an
+// automatic layout.
+const PipelineLayoutAuto uintptr = 0
+
+// RenderPipelineDescriptor describes a render pipeline.
+//
+// The zero value of every nested state is the WebGPU spec default:
+// PrimitiveState{} is a triangle list with CCW front faces and no culling,
+// and MultisampleState{} is read as one sample with all mask bits set (see
+// MultisampleState.Normalized).
+type RenderPipelineDescriptor struct {
+	// Label is an optional debug label.
+	Label string
+	// Layout is a handle to the pipeline layout (implementation-specific),
+	// or PipelineLayoutAuto for a layout derived from the shaders.
+	Layout uintptr
+	// Vertex describes the vertex shader and vertex buffer layouts.
+	Vertex VertexState
+	// Primitive describes primitive assembly and rasterization.
+	Primitive PrimitiveState
+	// DepthStencil describes depth and stencil testing (nil if none).
+	DepthStencil *DepthStencilState
+	// Multisample describes multisampling.
+	Multisample MultisampleState
+	// Fragment describes the fragment shader and color targets (nil if none).
+	Fragment *FragmentState
+}
+
+// IsAutoLayout returns true if the pipeline layout is derived from the shaders.
+func (d *RenderPipelineDescriptor) IsAutoLayout() bool {
+	return d.Layout == PipelineLayoutAuto
+}
+
+// ComputePipelineDescriptor describes a compute pipeline.
+type ComputePipelineDescriptor struct {
+	// Label is an optional debug label.
+	Label string
+	// Layout is a handle to the pipeline layout (implementation-specific),
+	// or PipelineLayoutAuto for a layout derived from the shader.
+	Layout uintptr
+	// Compute is the compute shader stage.
+	Compute ProgrammableStage
+}
+
+// IsAutoLayout returns true if the pipeline layout is derived from the shader.
+func (d *ComputePipelineDescriptor) IsAutoLayout() bool {
+	return d.Layout == PipelineLayoutAuto
+}
+
+// Normalized returns s with zero fields replaced by the WebGPU spec
+// defaults: a Count of 0 becomes 1 and a Mask of 0 becomes 0xFFFFFFFF.
+//
+// This lets MultisampleState{} stand for the default state. A zero sample
+// mask would discard every fragment, so it is never meaningful to request.
+func (s MultisampleState) Normalized() MultisampleState {
+	if s.Count == 0 {
+		s.Count = 1
+	}
+	if s.Mask == 0 {
+		s.Mask = 0xFFFFFFFF
+	}
+	return s
+}
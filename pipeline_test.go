@@ -0,0 +1,43 @@
+package gputypes
+
+import "testing"
+
+func TestPipelineDescriptor_ZeroValue(t *testing.T) {
+	var rp RenderPipelineDescriptor
+	if !rp.IsAutoLayout() {
+		t.Error("zero RenderPipelineDescriptor does not use an auto layout")
+	}
+	if rp.Primitive != DefaultPrimitiveState() {
+		t.Errorf("zero Primitive = %+v, want spec default", rp.Primitive)
+	}
+	if got, want := rp.Multisample.Normalized(), DefaultMultisampleState(); got != want {
+		t.Errorf("zero Multisample normalizes to %+v, want %+v", got, want)
+	}
+	if rp.DepthStencil != nil || rp.Fragment != nil {
+		t.Error("zero RenderPipelineDescriptor has optional states set")
+	}
+
+	var cp ComputePipelineDescriptor
+	if !cp.IsAutoLayout() {
+		t.Error("zero ComputePipelineDescriptor does not use an auto layout")
+	}
+	cp.Layout = 0x1234
+	if cp.IsAutoLayout() {
+		t.Error("explicit layout reported as auto")
+	}
+}
+
+func TestMultisampleState_Normalized(t *testing.T) {
+	tests := []struct {
+		in, want MultisampleState
+	}{
+		{MultisampleState{}, MultisampleState{Count: 1, Mask: 0xFFFFFFFF}},
+		{MultisampleState{Count: 4}, MultisampleState{Count: 4, Mask: 0xFFFFFFFF}},
+		{MultisampleState{Count: 4, Mask: 0x5, AlphaToCoverageEnabled: true}, MultisampleState{Count: 4, Mask: 0x5, AlphaToCoverageEnabled: true}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Normalized(); got != tt.want {
+			t.Errorf("%+v.Normalized() = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
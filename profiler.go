@@ -0,0 +1,332 @@
+package gputypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// Profiler errors.
+var (
+	// ErrProfilerState means a Profiler method was called out of order, such
+	// as EndScope without an open scope.
+	ErrProfilerState = errors.New("profiler call out of order")
+	// ErrProfilerQueries means a frame needs more timestamp queries than
+	// Profiler.QueryCount.
+	ErrProfilerQueries = errors.New("profiler out of timestamp queries")
+)
+
+// DefaultProfilerHistory is the number of resolved frames a Profiler keeps
+// when History is 0.
+const DefaultProfilerHistory = 120
+
+// Profiler measures GPU time of nested named scopes with timestamp queries
+// (FeatureTimestampQuery).
+//
+// Each frame, scopes are opened and closed with BeginScope and EndScope,
+// which hand out the timestamp query indices to write. Scope i of a frame
+// uses indices 2i and 2i+1, so a frame of n scopes needs 2n queries of a
+// timestamp query set, resolved into a BufferUsageQueryResolve buffer. Use
+// one query set per frame in flight. Once the results are read back, pass
+// the decoded ticks (see DecodeTimestamps) to Resolve.
+//
+// The zero value is ready to use. A Profiler is not safe for concurrent use.
+type Profiler struct {
+	// QueryCount is the size of the timestamp query set of each frame
+	// (0 means MaxQuerySetCount).
+	QueryCount uint32
+	// Period is the timestamp period in nanoseconds per tick (0 is read as 1).
+	Period float32
+	// History is the number of resolved frames kept for Stats and
+	// WriteChromeTrace (0 means DefaultProfilerHistory).
+	History int
+
+	open     *ProfiledFrame
+	stack    []int
+	pending  []*ProfiledFrame
+	resolved []*ProfiledFrame
+	next     uint64
+}
+
+// ProfileScope is one timed scope of a frame.
+type ProfileScope struct {
+	// Name is the scope name given to BeginScope.
+	Name string
+	// Path is the slash-separated names of the scope and its parents.
+	Path string
+	// Parent is the index of the enclosing scope in the frame, or -1.
+	Parent int
+	// Depth is the nesting depth; top-level scopes have depth 0.
+	Depth int
+	// BeginIndex and EndIndex are the timestamp query indices of the scope.
+	BeginIndex, EndIndex uint32
+	// BeginTick and EndTick are the raw timestamps, set by Resolve.
+	BeginTick, EndTick uint64
+	// Start is the scope start relative to the start of the frame, and
+	// Duration the scope length; both are set by Resolve.
+	Start, Duration time.Duration
+}
+
+// ProfiledFrame is the scopes of one frame, in BeginScope order.
+type ProfiledFrame struct {
+	// Frame is the frame number, counting from 0.
+	Frame uint64
+	// Scopes are the frame's scopes.
+	Scopes []ProfileScope
+	// Resolved is true once Resolve has filled in the timings.
+	Resolved bool
+	// BaseTick is the earliest scope timestamp, set by Resolve.
+	BaseTick uint64
+	// Duration is the time from the earliest scope start to the latest
+	// scope end, set by Resolve.
+	Duration time.Duration
+}
+
+// QueryCount returns the number of timestamp queries the frame uses.
+func (f *ProfiledFrame) QueryCount() uint32 {
+	return uint32(len(f.Scopes)) * 2
+}
+
+// BeginFrame starts recording a new frame and returns its number.
+func (p *Profiler) BeginFrame() (uint64, error) {
+	if p.open != nil {
+		return 0, fmt.Errorf("gputypes: %w: frame %d is still open", ErrProfilerState, p.open.Frame)
+	}
+	p.open = &ProfiledFrame{Frame: p.next}
+	p.next++
+	return p.open.Frame, nil
+}
+
+// BeginScope opens a scope nested in the innermost open scope and returns
+// the query index to write a timestamp to at its start. The scope's end
+// index is begin+1; see ScopeTimestampWrites for timing a whole pass.
+func (p *Profiler) BeginScope(name string) (begin uint32, err error) {
+	if p.open == nil {
+		return 0, fmt.Errorf("gputypes: %w: BeginScope outside a frame", ErrProfilerState)
+	}
+	f := p.open
+	limit := p.QueryCount
+	if limit == 0 {
+		limit = MaxQuerySetCount
+	}
+	begin = f.QueryCount()
+	if begin+2 > limit {
+		return 0, fmt.Errorf("gputypes: %w: scope %q needs queries %d and %d of %d", ErrProfilerQueries, name, begin, begin+1, limit)
+	}
+
+	s := ProfileScope{Name: name, Path: name, Parent: -1, BeginIndex: begin, EndIndex: begin + 1}
+	if n := len(p.stack); n > 0 {
+		parent := &f.Scopes[p.stack[n-1]]
+		s.Parent, s.Depth, s.Path = p.stack[n-1], parent.Depth+1, parent.Path+"/"+name
+	}
+	p.stack = append(p.stack, len(f.Scopes))
+	f.Scopes = append(f.Scopes, s)
+	return begin, nil
+}
+
+// EndScope closes the innermost open scope and returns the query index to
+// write a timestamp to at its end.
+func (p *Profiler) EndScope() (end uint32, err error) {
+	if p.open == nil || len(p.stack) == 0 {
+		return 0, fmt.Errorf("gputypes: %w: EndScope without an open scope", ErrProfilerState)
+	}
+	i := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	return p.open.Scopes[i].EndIndex, nil
+}
+
+// EndFrame finishes the open frame, which then waits for Resolve, and
+// returns its number and the number of queries to resolve, starting at 0.
+func (p *Profiler) EndFrame() (frame uint64, queries uint32, err error) {
+	switch {
+	case p.open == nil:
+		return 0, 0, fmt.Errorf("gputypes: %w: EndFrame without a frame", ErrProfilerState)
+	case len(p.stack) > 0:
+		return 0, 0, fmt.Errorf("gputypes: %w: scope %q is still open", ErrProfilerState, p.open.Scopes[p.stack[len(p.stack)-1]].Path)
+	}
+	f := p.open
+	p.open = nil
+	p.pending = append(p.pending, f)
+	return f.Frame, f.QueryCount(), nil
+}
+
+// ScopeTimestampWrites returns the timestamp writes that time a render or
+// compute pass as the scope whose BeginScope returned begin.
+func ScopeTimestampWrites(querySet uintptr, begin uint32) *PassTimestampWrites {
+	return &PassTimestampWrites{QuerySet: querySet, BeginningOfPassWriteIndex: begin, EndOfPassWriteIndex: begin + 1}
+}
+
+// Resolve fills in the timings of an ended frame from its resolved
+// timestamps, indexed by query, and adds it to the history.
+func (p *Profiler) Resolve(frame uint64, timestamps []uint64) error {
+	i := slices.IndexFunc(p.pending, func(f *ProfiledFrame) bool { return f.Frame == frame })
+	if i < 0 {
+		return fmt.Errorf("gputypes: %w: frame %d is not awaiting results", ErrProfilerState, frame)
+	}
+	f := p.pending[i]
+	if n := f.QueryCount(); uint32(len(timestamps)) < n {
+		return fmt.Errorf("gputypes: %w: frame %d has %d timestamps, want %d", ErrQueryResults, frame, len(timestamps), n)
+	}
+	p.pending = slices.Delete(p.pending, i, i+1)
+
+	period := p.Period
+	if period == 0 {
+		period = 1
+	}
+	if len(f.Scopes) > 0 {
+		f.BaseTick = math.MaxUint64
+		var last uint64
+		for j := range f.Scopes {
+			s := &f.Scopes[j]
+			s.BeginTick, s.EndTick = timestamps[s.BeginIndex], timestamps[s.EndIndex]
+			f.BaseTick = min(f.BaseTick, s.BeginTick)
+			last = max(last, s.EndTick)
+		}
+		for j := range f.Scopes {
+			s := &f.Scopes[j]
+			s.Start = TimestampDuration(f.BaseTick, s.BeginTick, period)
+			s.Duration = TimestampDuration(s.BeginTick, s.EndTick, period)
+		}
+		f.Duration = TimestampDuration(f.BaseTick, last, period)
+	}
+	f.Resolved = true
+
+	p.resolved = append(p.resolved, f)
+	history := p.History
+	if history <= 0 {
+		history = DefaultProfilerHistory
+	}
+	if n := len(p.resolved) - history; n > 0 {
+		p.resolved = slices.Delete(p.resolved, 0, n)
+	}
+	return nil
+}
+
+// Frames returns the resolved frames in the history, oldest first.
+func (p *Profiler) Frames() []*ProfiledFrame {
+	return slices.Clone(p.resolved)
+}
+
+// ProfileStats summarizes the per-frame GPU time of one scope path across
+// the resolved frames.
+type ProfileStats struct {
+	// Path is the scope path, or "" for whole frames.
+	Path string
+	// Frames is the number of frames the scope appears in.
+	Frames int
+	// Min, Avg and P95 are the minimum, mean and 95th percentile time per
+	// frame. A path that occurs several times in a frame counts its total.
+	Min, Avg, P95 time.Duration
+}
+
+// FrameStats returns statistics of whole-frame GPU time.
+func (p *Profiler) FrameStats() ProfileStats {
+	times := make([]time.Duration, 0, len(p.resolved))
+	for _, f := range p.resolved {
+		times = append(times, f.Duration)
+	}
+	return summarize("", times)
+}
+
+// Stats returns per-scope statistics over the resolved frames, in order of
+// first appearance.
+func (p *Profiler) Stats() []ProfileStats {
+	var paths []string
+	times := make(map[string][]time.Duration)
+	for _, f := range p.resolved {
+		perFrame := make(map[string]time.Duration)
+		var order []string
+		for _, s := range f.Scopes {
+			if _, ok := perFrame[s.Path]; !ok {
+				order = append(order, s.Path)
+			}
+			perFrame[s.Path] += s.Duration
+		}
+		for _, path := range order {
+			if _, ok := times[path]; !ok {
+				paths = append(paths, path)
+			}
+			times[path] = append(times[path], perFrame[path])
+		}
+	}
+	stats := make([]ProfileStats, len(paths))
+	for i, path := range paths {
+		stats[i] = summarize(path, times[path])
+	}
+	return stats
+}
+
+// summarize computes min, mean and nearest-rank 95th percentile.
+func summarize(path string, times []time.Duration) ProfileStats {
+	s := ProfileStats{Path: path, Frames: len(times)}
+	if len(times) == 0 {
+		return s
+	}
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	var sum time.Duration
+	for _, t := range sorted {
+		sum += t
+	}
+	s.Min = sorted[0]
+	s.Avg = sum / time.Duration(len(sorted))
+	s.P95 = sorted[(len(sorted)*95+99)/100-1]
+	return s
+}
+
+// chromeTraceEvent is a complete ("X") event of the Chrome trace format.
+type chromeTraceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the resolved frames as Chrome trace event JSON,
+// viewable in chrome://tracing or Perfetto. Times are microseconds from the
+// earliest frame in the history; nested scopes nest in the viewer.
+func (p *Profiler) WriteChromeTrace(w io.Writer) error {
+	period := p.Period
+	if period == 0 {
+		period = 1
+	}
+	origin := uint64(math.MaxUint64)
+	for _, f := range p.resolved {
+		if len(f.Scopes) > 0 {
+			origin = min(origin, f.BaseTick)
+		}
+	}
+	us := func(d time.Duration) float64 { return float64(d) / float64(time.Microsecond) }
+
+	events := make([]chromeTraceEvent, 0)
+	for _, f := range p.resolved {
+		if len(f.Scopes) == 0 {
+			continue
+		}
+		start := TimestampDuration(origin, f.BaseTick, period)
+		events = append(events, chromeTraceEvent{
+			Name: fmt.Sprintf("frame %d", f.Frame), Cat: "frame", Ph: "X",
+			Ts: us(start), Dur: us(f.Duration),
+		})
+		for _, s := range f.Scopes {
+			events = append(events, chromeTraceEvent{
+				Name: s.Name, Cat: "gpu", Ph: "X",
+				Ts: us(start + s.Start), Dur: us(s.Duration),
+				Args: map[string]any{"frame": f.Frame, "path": s.Path},
+			})
+		}
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []chromeTraceEvent `json:"traceEvents"`
+		DisplayTimeUnit string             `json:"displayTimeUnit"`
+	}{events, "ns"})
+}
@@ -0,0 +1,179 @@
+package gputypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// profileFrame records a frame with a "shadow" scope and a "main" scope
+// containing "opaque", and resolves it with ticks starting at base.
+func profileFrame(t *testing.T, p *Profiler, base uint64, shadow, opaque uint64) {
+	t.Helper()
+	must := func(_ uint32, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := p.BeginFrame(); err != nil {
+		t.Fatal(err)
+	}
+	must(p.BeginScope("shadow"))
+	must(p.EndScope())
+	must(p.BeginScope("main"))
+	must(p.BeginScope("opaque"))
+	must(p.EndScope())
+	must(p.EndScope())
+	frame, n, err := p.EndFrame()
+	if err != nil || n != 6 {
+		t.Fatalf("EndFrame() = %d, %d, %v; want 6 queries", frame, n, err)
+	}
+	ts := []uint64{
+		base, base + shadow, // shadow
+		base + shadow, base + shadow + opaque + 10, // main
+		base + shadow + 5, base + shadow + 5 + opaque, // opaque
+	}
+	if err := p.Resolve(frame, ts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProfiler_Scopes(t *testing.T) {
+	p := &Profiler{Period: 2}
+	profileFrame(t, p, 1000, 100, 50)
+
+	frames := p.Frames()
+	if len(frames) != 1 || !frames[0].Resolved {
+		t.Fatalf("Frames() = %+v", frames)
+	}
+	f := frames[0]
+	if f.Duration != 320*time.Nanosecond {
+		t.Errorf("frame Duration = %v, want 320ns", f.Duration)
+	}
+	want := []struct {
+		path   string
+		parent int
+		depth  int
+		start  time.Duration
+		dur    time.Duration
+	}{
+		{"shadow", -1, 0, 0, 200},
+		{"main", -1, 0, 200, 120},
+		{"main/opaque", 1, 1, 210, 100},
+	}
+	for i, w := range want {
+		s := f.Scopes[i]
+		if s.Path != w.path || s.Parent != w.parent || s.Depth != w.depth || s.Start != w.start || s.Duration != w.dur {
+			t.Errorf("scope %d = %+v, want %+v", i, s, w)
+		}
+		if s.BeginIndex != uint32(2*i) || s.EndIndex != uint32(2*i+1) {
+			t.Errorf("scope %d indices = %d, %d", i, s.BeginIndex, s.EndIndex)
+		}
+	}
+
+	w := ScopeTimestampWrites(7, f.Scopes[1].BeginIndex)
+	if err := w.Validate(6); err != nil || w.EndOfPassWriteIndex != 3 {
+		t.Errorf("ScopeTimestampWrites() = %+v, %v", w, err)
+	}
+}
+
+func TestProfiler_Errors(t *testing.T) {
+	p := &Profiler{QueryCount: 4}
+	if _, err := p.BeginScope("a"); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("BeginScope outside frame error = %v", err)
+	}
+	if _, _, err := p.EndFrame(); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("EndFrame without frame error = %v", err)
+	}
+	p.BeginFrame()
+	if _, err := p.BeginFrame(); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("nested BeginFrame error = %v", err)
+	}
+	p.BeginScope("a")
+	p.BeginScope("b")
+	if _, err := p.BeginScope("c"); !errors.Is(err, ErrProfilerQueries) {
+		t.Errorf("third scope with 4 queries error = %v", err)
+	}
+	if _, _, err := p.EndFrame(); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("EndFrame with open scope error = %v", err)
+	}
+	p.EndScope()
+	p.EndScope()
+	if _, err := p.EndScope(); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("unbalanced EndScope error = %v", err)
+	}
+	frame, _, _ := p.EndFrame()
+	if err := p.Resolve(frame, []uint64{1, 2, 3}); !errors.Is(err, ErrQueryResults) {
+		t.Errorf("short Resolve error = %v", err)
+	}
+	if err := p.Resolve(frame+1, nil); !errors.Is(err, ErrProfilerState) {
+		t.Errorf("Resolve unknown frame error = %v", err)
+	}
+}
+
+func TestProfiler_Stats(t *testing.T) {
+	p := &Profiler{History: 20}
+	// 25 frames; only the last 20 are kept: shadow 6..25, opaque 1.
+	for i := range uint64(25) {
+		profileFrame(t, p, i*1000, i+1, 1)
+	}
+	if n := len(p.Frames()); n != 20 {
+		t.Fatalf("kept %d frames, want 20", n)
+	}
+
+	stats := p.Stats()
+	if len(stats) != 3 || stats[0].Path != "shadow" || stats[2].Path != "main/opaque" {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	shadow := stats[0]
+	if shadow.Frames != 20 || shadow.Min != 6 || shadow.Avg != 15 || shadow.P95 != 24 {
+		t.Errorf("shadow stats = %+v, want min 6 avg 15 p95 24", shadow)
+	}
+	if frame := p.FrameStats(); frame.Min != 6+11 || frame.Frames != 20 {
+		t.Errorf("FrameStats() = %+v", frame)
+	}
+	if s := (&Profiler{}).FrameStats(); s.Frames != 0 || s.Avg != 0 {
+		t.Errorf("empty FrameStats() = %+v", s)
+	}
+}
+
+func TestProfiler_WriteChromeTrace(t *testing.T) {
+	p := &Profiler{Period: 1000}
+	profileFrame(t, p, 5000, 2, 1)
+	profileFrame(t, p, 6000, 2, 1)
+
+	var buf bytes.Buffer
+	if err := p.WriteChromeTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name string  `json:"name"`
+			Ph   string  `json:"ph"`
+			Ts   float64 `json:"ts"`
+			Dur  float64 `json:"dur"`
+			Args struct {
+				Path string `json:"path"`
+			} `json:"args"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	ev := trace.TraceEvents
+	if len(ev) != 8 {
+		t.Fatalf("got %d events, want 8", len(ev))
+	}
+	if ev[0].Name != "frame 0" || ev[0].Ts != 0 || ev[0].Dur != 13 {
+		t.Errorf("frame event = %+v", ev[0])
+	}
+	if ev[3].Args.Path != "main/opaque" || ev[3].Ts != 7 || ev[3].Dur != 1 || ev[3].Ph != "X" {
+		t.Errorf("opaque event = %+v", ev[3])
+	}
+	if ev[4].Name != "frame 1" || ev[4].Ts != 1000 {
+		t.Errorf("second frame event = %+v", ev[4])
+	}
+}
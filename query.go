@@ -0,0 +1,271 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// QueryType describes the kind of queries in a query set.
+type QueryType uint32
+
+const (
+	// QueryTypeUndefined is an undefined query type.
+	QueryTypeUndefined QueryType = 0x00000000
+	// QueryTypeOcclusion counts the samples that pass the depth and stencil tests.
+	QueryTypeOcclusion QueryType = 0x00000001
+	// QueryTypeTimestamp records GPU timestamps (requires FeatureTimestampQuery).
+	QueryTypeTimestamp QueryType = 0x00000002
+	// QueryTypePipelineStatistics counts pipeline statistics (native extension,
+	// requires FeaturePipelineStatisticsQuery). The value matches wgpu-native's
+	// WGPUNativeQueryType_PipelineStatistics.
+	QueryTypePipelineStatistics QueryType = 0x00030000
+)
+
+// String returns the query type name.
+func (t QueryType) String() string {
+	switch t {
+	case QueryTypeUndefined:
+		return "Undefined"
+	case QueryTypeOcclusion:
+		return "Occlusion"
+	case QueryTypeTimestamp:
+		return "Timestamp"
+	case QueryTypePipelineStatistics:
+		return "PipelineStatistics"
+	default:
+		return "Unknown"
+	}
+}
+
+// PipelineStatisticName identifies a counter recorded by a pipeline
+// statistics query. Values match wgpu-native.
+type PipelineStatisticName uint32
+
+const (
+	// PipelineStatisticVertexShaderInvocations counts vertex shader invocations.
+	PipelineStatisticVertexShaderInvocations PipelineStatisticName = 0x00000000
+	// PipelineStatisticClipperInvocations counts primitives sent to the clipper.
+	PipelineStatisticClipperInvocations PipelineStatisticName = 0x00000001
+	// PipelineStatisticClipperPrimitivesOut counts primitives output by the clipper.
+	PipelineStatisticClipperPrimitivesOut PipelineStatisticName = 0x00000002
+	// PipelineStatisticFragmentShaderInvocations counts fragment shader invocations.
+	PipelineStatisticFragmentShaderInvocations PipelineStatisticName = 0x00000003
+	// PipelineStatisticComputeShaderInvocations counts compute shader invocations.
+	PipelineStatisticComputeShaderInvocations PipelineStatisticName = 0x00000004
+)
+
+// String returns the statistic name.
+func (n PipelineStatisticName) String() string {
+	switch n {
+	case PipelineStatisticVertexShaderInvocations:
+		return "VertexShaderInvocations"
+	case PipelineStatisticClipperInvocations:
+		return "ClipperInvocations"
+	case PipelineStatisticClipperPrimitivesOut:
+		return "ClipperPrimitivesOut"
+	case PipelineStatisticFragmentShaderInvocations:
+		return "FragmentShaderInvocations"
+	case PipelineStatisticComputeShaderInvocations:
+		return "ComputeShaderInvocations"
+	default:
+		return "Unknown"
+	}
+}
+
+const (
+	// MaxQuerySetCount is the maximum number of queries in a query set.
+	MaxQuerySetCount = 4096
+	// QueryResolveBufferAlignment is the required alignment of the
+	// destination offset when resolving a query set into a buffer.
+	QueryResolveBufferAlignment = 256
+	// QueryResultSize is the size in bytes of one resolved query value.
+	QueryResultSize = 8
+)
+
+// Query set errors.
+var (
+	// ErrQueryType means the query type is undefined or unknown.
+	ErrQueryType = errors.New("invalid query type")
+	// ErrQueryCount means the query count is 0 or exceeds MaxQuerySetCount.
+	ErrQueryCount = errors.New("invalid query count")
+	// ErrPipelineStatistics means the pipeline statistics list is empty,
+	// repeats a name, contains an unknown name, or is set for another query type.
+	ErrPipelineStatistics = errors.New("invalid pipeline statistics")
+	// ErrQueryResults means resolved query data has the wrong size.
+	ErrQueryResults = errors.New("invalid query result data")
+)
+
+// QuerySetDescriptor describes a query set.
+type QuerySetDescriptor struct {
+	// Label is an optional debug label.
+	Label string
+	// Type is the kind of queries in the set.
+	Type QueryType
+	// Count is the number of queries.
+	Count uint32
+	// PipelineStatistics lists the counters recorded by each query of a
+	// QueryTypePipelineStatistics set. It must be empty for other types.
+	PipelineStatistics []PipelineStatisticName
+}
+
+// Validate checks the descriptor against the enabled features.
+func (d *QuerySetDescriptor) Validate(features Features) error {
+	switch d.Type {
+	case QueryTypeOcclusion:
+	case QueryTypeTimestamp:
+		if !features.Contains(FeatureTimestampQuery) {
+			return fmt.Errorf("gputypes: %w: %s queries need %s", ErrMissingFeature, d.Type, FeatureTimestampQuery)
+		}
+	case QueryTypePipelineStatistics:
+		if !features.Contains(FeaturePipelineStatisticsQuery) {
+			return fmt.Errorf("gputypes: %w: %s queries need %s", ErrMissingFeature, d.Type, FeaturePipelineStatisticsQuery)
+		}
+	default:
+		return fmt.Errorf("gputypes: %w: %s", ErrQueryType, d.Type)
+	}
+	if d.Count == 0 || d.Count > MaxQuerySetCount {
+		return fmt.Errorf("gputypes: %w: %d not in [1, %d]", ErrQueryCount, d.Count, MaxQuerySetCount)
+	}
+
+	if d.Type != QueryTypePipelineStatistics {
+		if len(d.PipelineStatistics) != 0 {
+			return fmt.Errorf("gputypes: %w: set for %s queries", ErrPipelineStatistics, d.Type)
+		}
+		return nil
+	}
+	if len(d.PipelineStatistics) == 0 {
+		return fmt.Errorf("gputypes: %w: empty", ErrPipelineStatistics)
+	}
+	var seen [PipelineStatisticComputeShaderInvocations + 1]bool
+	for _, n := range d.PipelineStatistics {
+		if n > PipelineStatisticComputeShaderInvocations {
+			return fmt.Errorf("gputypes: %w: unknown name %d", ErrPipelineStatistics, n)
+		}
+		if seen[n] {
+			return fmt.Errorf("gputypes: %w: %s listed twice", ErrPipelineStatistics, n)
+		}
+		seen[n] = true
+	}
+	return nil
+}
+
+// ResultStride returns the size in bytes of one resolved query: one
+// 64-bit value, or one per pipeline statistic.
+func (d *QuerySetDescriptor) ResultStride() uint64 {
+	if d.Type == QueryTypePipelineStatistics {
+		return uint64(len(d.PipelineStatistics)) * QueryResultSize
+	}
+	return QueryResultSize
+}
+
+// ResultSize returns the size in bytes of all resolved queries in the set.
+func (d *QuerySetDescriptor) ResultSize() uint64 {
+	return uint64(d.Count) * d.ResultStride()
+}
+
+// decodeUint64s decodes little-endian 64-bit query values.
+func decodeUint64s(data []byte) ([]uint64, error) {
+	if len(data)%QueryResultSize != 0 {
+		return nil, fmt.Errorf("gputypes: %w: length %d is not a multiple of %d", ErrQueryResults, len(data), QueryResultSize)
+	}
+	values := make([]uint64, len(data)/QueryResultSize)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint64(data[i*QueryResultSize:])
+	}
+	return values, nil
+}
+
+// DecodeOcclusionResults decodes a resolved occlusion query buffer into the
+// number of samples that passed for each query. A non-zero count means
+// something was visible.
+func DecodeOcclusionResults(data []byte) ([]uint64, error) {
+	return decodeUint64s(data)
+}
+
+// DecodeTimestamps decodes a resolved timestamp query buffer into raw GPU
+// ticks. Convert tick differences with TimestampDuration.
+func DecodeTimestamps(data []byte) ([]uint64, error) {
+	return decodeUint64s(data)
+}
+
+// TimestampDuration converts the ticks between two timestamps to a
+// duration, given the timestamp period in nanoseconds per tick. It returns
+// 0 if end is before begin, as can happen when a GPU resets its counter.
+func TimestampDuration(begin, end uint64, period float32) time.Duration {
+	if end < begin {
+		return 0
+	}
+	return time.Duration(float64(end-begin) * float64(period))
+}
+
+// PipelineStatistics holds the counters of one pipeline statistics query.
+// Counters the query set did not record are 0.
+type PipelineStatistics struct {
+	VertexShaderInvocations   uint64
+	ClipperInvocations        uint64
+	ClipperPrimitivesOut      uint64
+	FragmentShaderInvocations uint64
+	ComputeShaderInvocations  uint64
+}
+
+// Get returns the counter for name, or 0 for unknown names.
+func (s *PipelineStatistics) Get(name PipelineStatisticName) uint64 {
+	if p := s.field(name); p != nil {
+		return *p
+	}
+	return 0
+}
+
+// field returns a pointer to the counter for name, or nil.
+func (s *PipelineStatistics) field(name PipelineStatisticName) *uint64 {
+	switch name {
+	case PipelineStatisticVertexShaderInvocations:
+		return &s.VertexShaderInvocations
+	case PipelineStatisticClipperInvocations:
+		return &s.ClipperInvocations
+	case PipelineStatisticClipperPrimitivesOut:
+		return &s.ClipperPrimitivesOut
+	case PipelineStatisticFragmentShaderInvocations:
+		return &s.FragmentShaderInvocations
+	case PipelineStatisticComputeShaderInvocations:
+		return &s.ComputeShaderInvocations
+	default:
+		return nil
+	}
+}
+
+// DecodePipelineStatistics decodes a resolved pipeline statistics query
+// buffer recorded with the given statistic names.
+//
+// Each query resolves to one 64-bit value per statistic, ordered by
+// ascending PipelineStatisticName regardless of the order of names, as
+// wgpu does.
+func DecodePipelineStatistics(data []byte, names []PipelineStatisticName) ([]PipelineStatistics, error) {
+	order := slices.Clone(names)
+	slices.Sort(order)
+	if len(order) == 0 || len(slices.Compact(order)) != len(names) {
+		return nil, fmt.Errorf("gputypes: %w: names must be non-empty and distinct", ErrPipelineStatistics)
+	}
+	values, err := decodeUint64s(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(values)%len(order) != 0 {
+		return nil, fmt.Errorf("gputypes: %w: %d values do not divide into queries of %d statistics", ErrQueryResults, len(values), len(order))
+	}
+
+	out := make([]PipelineStatistics, len(values)/len(order))
+	for i := range out {
+		for j, name := range order {
+			p := out[i].field(name)
+			if p == nil {
+				return nil, fmt.Errorf("gputypes: %w: unknown name %d", ErrPipelineStatistics, name)
+			}
+			*p = values[i*len(order)+j]
+		}
+	}
+	return out, nil
+}
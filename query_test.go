@@ -0,0 +1,141 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryType_String(t *testing.T) {
+	tests := []struct {
+		t    QueryType
+		want string
+	}{
+		{QueryTypeUndefined, "Undefined"},
+		{QueryTypeOcclusion, "Occlusion"},
+		{QueryTypeTimestamp, "Timestamp"},
+		{QueryTypePipelineStatistics, "PipelineStatistics"},
+		{QueryType(99), "Unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.t.String(); got != tt.want {
+			t.Errorf("QueryType(%d).String() = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+	if got := PipelineStatisticClipperPrimitivesOut.String(); got != "ClipperPrimitivesOut" {
+		t.Errorf("PipelineStatisticClipperPrimitivesOut.String() = %q", got)
+	}
+}
+
+func TestQuerySetDescriptor_Validate(t *testing.T) {
+	all := Features(FeatureTimestampQuery | FeaturePipelineStatisticsQuery)
+	stats := []PipelineStatisticName{PipelineStatisticFragmentShaderInvocations, PipelineStatisticVertexShaderInvocations}
+
+	tests := []struct {
+		name     string
+		desc     QuerySetDescriptor
+		features Features
+		want     error
+	}{
+		{"occlusion", QuerySetDescriptor{Type: QueryTypeOcclusion, Count: 16}, 0, nil},
+		{"timestamp", QuerySetDescriptor{Type: QueryTypeTimestamp, Count: MaxQuerySetCount}, all, nil},
+		{"statistics", QuerySetDescriptor{Type: QueryTypePipelineStatistics, Count: 1, PipelineStatistics: stats}, all, nil},
+		{"undefined type", QuerySetDescriptor{Count: 1}, all, ErrQueryType},
+		{"timestamp feature", QuerySetDescriptor{Type: QueryTypeTimestamp, Count: 2}, 0, ErrMissingFeature},
+		{"statistics feature", QuerySetDescriptor{Type: QueryTypePipelineStatistics, Count: 1, PipelineStatistics: stats},
+			Features(FeatureTimestampQuery), ErrMissingFeature},
+		{"zero count", QuerySetDescriptor{Type: QueryTypeOcclusion}, 0, ErrQueryCount},
+		{"too many", QuerySetDescriptor{Type: QueryTypeOcclusion, Count: MaxQuerySetCount + 1}, 0, ErrQueryCount},
+		{"no statistics", QuerySetDescriptor{Type: QueryTypePipelineStatistics, Count: 1}, all, ErrPipelineStatistics},
+		{"duplicate statistic", QuerySetDescriptor{Type: QueryTypePipelineStatistics, Count: 1,
+			PipelineStatistics: []PipelineStatisticName{1, 1}}, all, ErrPipelineStatistics},
+		{"statistics on occlusion", QuerySetDescriptor{Type: QueryTypeOcclusion, Count: 1, PipelineStatistics: stats},
+			0, ErrPipelineStatistics},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.desc.Validate(tt.features)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySetDescriptor_ResultSize(t *testing.T) {
+	d := QuerySetDescriptor{Type: QueryTypeTimestamp, Count: 4}
+	if got := d.ResultSize(); got != 32 {
+		t.Errorf("timestamp ResultSize() = %d, want 32", got)
+	}
+	d = QuerySetDescriptor{Type: QueryTypePipelineStatistics, Count: 4, PipelineStatistics: []PipelineStatisticName{0, 3, 4}}
+	if got := d.ResultStride(); got != 24 {
+		t.Errorf("statistics ResultStride() = %d, want 24", got)
+	}
+	if got := d.ResultSize(); got != 96 {
+		t.Errorf("statistics ResultSize() = %d, want 96", got)
+	}
+}
+
+func encodeQueryValues(values ...uint64) []byte {
+	data := make([]byte, 0, len(values)*QueryResultSize)
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	return data
+}
+
+func TestDecodeQueryResults(t *testing.T) {
+	got, err := DecodeOcclusionResults(encodeQueryValues(0, 42))
+	if err != nil || len(got) != 2 || got[0] != 0 || got[1] != 42 {
+		t.Errorf("DecodeOcclusionResults() = %v, %v", got, err)
+	}
+	ts, err := DecodeTimestamps(encodeQueryValues(1000, 1<<40))
+	if err != nil || ts[1] != 1<<40 {
+		t.Errorf("DecodeTimestamps() = %v, %v", ts, err)
+	}
+	if _, err := DecodeTimestamps(make([]byte, 12)); !errors.Is(err, ErrQueryResults) {
+		t.Errorf("DecodeTimestamps(12 bytes) error = %v, want ErrQueryResults", err)
+	}
+}
+
+func TestTimestampDuration(t *testing.T) {
+	if got := TimestampDuration(100, 1100, 1); got != time.Microsecond {
+		t.Errorf("TimestampDuration(100, 1100, 1) = %v, want 1µs", got)
+	}
+	if got := TimestampDuration(0, 1000, 41.666668); got != 41666*time.Nanosecond {
+		t.Errorf("TimestampDuration(0, 1000, 41.67) = %v", got)
+	}
+	if got := TimestampDuration(10, 5, 1); got != 0 {
+		t.Errorf("TimestampDuration(10, 5, 1) = %v, want 0", got)
+	}
+}
+
+func TestDecodePipelineStatistics(t *testing.T) {
+	// Values resolve in ascending name order: vertex, then fragment.
+	names := []PipelineStatisticName{PipelineStatisticFragmentShaderInvocations, PipelineStatisticVertexShaderInvocations}
+	got, err := DecodePipelineStatistics(encodeQueryValues(3, 900, 6, 1800), names)
+	if err != nil {
+		t.Fatalf("DecodePipelineStatistics() error = %v", err)
+	}
+	want := []PipelineStatistics{
+		{VertexShaderInvocations: 3, FragmentShaderInvocations: 900},
+		{VertexShaderInvocations: 6, FragmentShaderInvocations: 1800},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("DecodePipelineStatistics() = %+v, want %+v", got, want)
+	}
+	if v := got[1].Get(PipelineStatisticFragmentShaderInvocations); v != 1800 {
+		t.Errorf("Get(FragmentShaderInvocations) = %d, want 1800", v)
+	}
+
+	if _, err := DecodePipelineStatistics(encodeQueryValues(1, 2, 3), names); !errors.Is(err, ErrQueryResults) {
+		t.Errorf("odd value count error = %v, want ErrQueryResults", err)
+	}
+	if _, err := DecodePipelineStatistics(nil, []PipelineStatisticName{0, 0}); !errors.Is(err, ErrPipelineStatistics) {
+		t.Errorf("duplicate names error = %v, want ErrPipelineStatistics", err)
+	}
+	if _, err := DecodePipelineStatistics(encodeQueryValues(1), []PipelineStatisticName{9}); !errors.Is(err, ErrPipelineStatistics) {
+		t.Errorf("unknown name error = %v, want ErrPipelineStatistics", err)
+	}
+}
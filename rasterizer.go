@@ -0,0 +1,572 @@
+package gputypes
+
+import (
+	"fmt"
+	"iter"
+	"math"
+)
+
+// Viewport is the viewport transform from normalized device coordinates to
+// framebuffer coordinates, as set by a render pass.
+//
+// The zero value covers the whole render target with depth range [0, 1].
+type Viewport struct {
+	// X and Y are the framebuffer coordinates of the top-left corner.
+	X, Y float64
+	// Width and Height are the size of the viewport in pixels.
+	Width, Height float64
+	// MinDepth and MaxDepth are the depth range NDC z in [0, 1] maps to.
+	MinDepth, MaxDepth float64
+}
+
+// StandardSamplePositions returns the standard sample positions for a
+// sample count, as offsets in [0, 1) from the top-left corner of a pixel,
+// in sample index order. WebGPU uses the 1 and 4 sample patterns; 2, 8 and
+// 16 follow the same (Direct3D) standard.
+//
+// Returns nil for unsupported counts.
+func StandardSamplePositions(count uint32) [][2]float64 {
+	var grid [][2]int
+	switch count {
+	case 1:
+		grid = [][2]int{{0, 0}}
+	case 2:
+		grid = [][2]int{{4, 4}, {-4, -4}}
+	case 4:
+		grid = [][2]int{{-2, -6}, {6, -2}, {-6, 2}, {2, 6}}
+	case 8:
+		grid = [][2]int{{1, -3}, {-1, 3}, {5, 1}, {-3, -5}, {-5, 5}, {-7, -1}, {3, 7}, {7, -7}}
+	case 16:
+		grid = [][2]int{
+			{1, 1}, {-1, -3}, {-3, 2}, {4, -1}, {-5, -2}, {2, 5}, {5, 3}, {3, -5},
+			{-2, 6}, {0, -7}, {-4, -6}, {-6, 4}, {-8, 0}, {7, -4}, {6, 7}, {-7, -8},
+		}
+	default:
+		return nil
+	}
+	// Standard patterns are specified in 1/16 pixel units from the center.
+	positions := make([][2]float64, len(grid))
+	for i, g := range grid {
+		positions[i] = [2]float64{0.5 + float64(g[0])/16, 0.5 + float64(g[1])/16}
+	}
+	return positions
+}
+
+// RasterFragment is one pixel covered by a primitive.
+type RasterFragment struct {
+	// X and Y are the pixel coordinates in the render target.
+	X, Y uint32
+	// Primitive is the index of the primitive in draw order.
+	Primitive int
+	// Vertices are the indices into the position slice of the primitive's
+	// vertices, in the order the barycentrics refer to. Unused entries
+	// (the third for lines, the last two for points) are -1.
+	Vertices [3]int
+	// FrontFacing is the @builtin(front_facing) value; always true for
+	// points and lines.
+	FrontFacing bool
+	// Coverage has bit i set if sample i is covered, after the multisample
+	// mask is applied. It is never 0.
+	Coverage uint32
+	// Barycentric holds the perspective-correct weights of Vertices at the
+	// pixel center, for @interpolate(perspective) inputs.
+	Barycentric [3]float64
+	// LinearBarycentric holds the screen-space weights of Vertices at the
+	// pixel center, for @interpolate(linear) inputs.
+	LinearBarycentric [3]float64
+	// Depth is the depth at the pixel center, in the viewport depth range
+	// and including depth bias.
+	Depth float64
+	// SampleDepth holds the depth of each sample; entries for samples not
+	// in Coverage are 0.
+	SampleDepth []float64
+}
+
+// Rasterizer is a deterministic CPU reference rasterizer following the
+// WebGPU rasterization rules.
+//
+// It assembles primitives by topology, culls by FrontFace and CullMode,
+// clips against w > 0 and the depth range (unless UnclippedDepth), applies
+// the viewport transform and rasterizes with the top-left fill rule at the
+// standard sample positions. Triangles are covered where a sample is inside
+// all edges; lines as 1-pixel-wide rectangles along the segment; points as
+// 1-pixel squares. Alpha-to-coverage depends on shader output and is left
+// to the caller.
+type Rasterizer struct {
+	// Primitive is the primitive state of the pipeline.
+	Primitive PrimitiveState
+	// Multisample is the multisample state of the pipeline.
+	Multisample MultisampleState
+	// DepthStencil supplies the depth bias and depth format (nil if none).
+	DepthStencil *DepthStencilState
+	// Viewport is the viewport transform.
+	Viewport Viewport
+	// Width and Height are the render target size in pixels.
+	Width, Height uint32
+}
+
+// Rasterize yields the fragments of the primitives formed by the
+// clip-space positions, in primitive order and then row-major pixel order.
+//
+// Strip topologies do not handle primitive restart; split strips or
+// convert them with IndexFormat.StripToList first. An unsupported sample
+// count is yielded as an error with a zero fragment.
+func (r *Rasterizer) Rasterize(positions [][4]float64) iter.Seq2[RasterFragment, error] {
+	return func(yield func(RasterFragment, error) bool) {
+		ms := r.Multisample.Normalized()
+		samples := StandardSamplePositions(ms.Count)
+		if samples == nil {
+			yield(RasterFragment{}, fmt.Errorf("gputypes: unsupported sample count %d", ms.Count))
+			return
+		}
+		s := &raster{
+			r:       r,
+			vp:      r.viewport(),
+			samples: samples,
+			mask:    uint32(ms.Mask) & (1<<ms.Count - 1),
+			yield:   yield,
+		}
+		s.bounds = [4]float64{
+			max(s.vp.X, 0), max(s.vp.Y, 0),
+			min(s.vp.X+s.vp.Width, float64(r.Width)), min(s.vp.Y+s.vp.Height, float64(r.Height)),
+		}
+
+		n := len(positions)
+		prim := 0
+		tri := func(a, b, c int) bool {
+			ok := s.triangle(prim, [3]int{a, b, c}, [3][4]float64{positions[a], positions[b], positions[c]})
+			prim++
+			return ok
+		}
+		line := func(a, b int) bool {
+			ok := s.line(prim, [2]int{a, b}, [2][4]float64{positions[a], positions[b]})
+			prim++
+			return ok
+		}
+
+		switch r.Primitive.Topology {
+		case PrimitiveTopologyPointList:
+			for i := range n {
+				if !s.point(i, i, positions[i]) {
+					return
+				}
+			}
+		case PrimitiveTopologyLineList:
+			for i := 0; i+1 < n; i += 2 {
+				if !line(i, i+1) {
+					return
+				}
+			}
+		case PrimitiveTopologyLineStrip:
+			for i := 0; i+1 < n; i++ {
+				if !line(i, i+1) {
+					return
+				}
+			}
+		case PrimitiveTopologyTriangleList:
+			for i := 0; i+2 < n; i += 3 {
+				if !tri(i, i+1, i+2) {
+					return
+				}
+			}
+		case PrimitiveTopologyTriangleStrip:
+			for i := 0; i+2 < n; i++ {
+				a, b := i, i+1
+				if i%2 == 1 {
+					a, b = b, a
+				}
+				if !tri(a, b, i+2) {
+					return
+				}
+			}
+		default:
+			yield(RasterFragment{}, fmt.Errorf("gputypes: unsupported topology %s", r.Primitive.Topology))
+		}
+	}
+}
+
+// viewport returns the viewport with the zero value expanded to the full target.
+func (r *Rasterizer) viewport() Viewport {
+	if r.Viewport == (Viewport{}) {
+		return Viewport{Width: float64(r.Width), Height: float64(r.Height), MaxDepth: 1}
+	}
+	return r.Viewport
+}
+
+// wClipEpsilon is the smallest w kept when clipping against the w > 0 plane.
+const wClipEpsilon = 1e-6
+
+// clipVertex is a clip-space position with its barycentric weights
+// relative to the original primitive.
+type clipVertex struct {
+	pos  [4]float64
+	bary [3]float64
+}
+
+// lerp interpolates between two clip vertices.
+func (a clipVertex) lerp(b clipVertex, t float64) clipVertex {
+	var out clipVertex
+	for i := range 4 {
+		out.pos[i] = a.pos[i] + (b.pos[i]-a.pos[i])*t
+	}
+	for i := range 3 {
+		out.bary[i] = a.bary[i] + (b.bary[i]-a.bary[i])*t
+	}
+	return out
+}
+
+// clipW clips a polygon against the w >= wClipEpsilon half-space.
+func clipW(poly []clipVertex) []clipVertex {
+	var out []clipVertex
+	for i, cur := range poly {
+		prev := poly[(i+len(poly)-1)%len(poly)]
+		curIn, prevIn := cur.pos[3] >= wClipEpsilon, prev.pos[3] >= wClipEpsilon
+		if curIn != prevIn {
+			t := (wClipEpsilon - prev.pos[3]) / (cur.pos[3] - prev.pos[3])
+			out = append(out, prev.lerp(cur, t))
+		}
+		if curIn {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// screenVertex is a vertex after perspective divide and viewport transform.
+type screenVertex struct {
+	x, y float64 // framebuffer coordinates
+	z    float64 // NDC depth
+	invW float64
+	bary [3]float64
+}
+
+// raster holds the per-draw state of Rasterize.
+type raster struct {
+	r       *Rasterizer
+	vp      Viewport
+	samples [][2]float64
+	mask    uint32
+	bounds  [4]float64 // minX, minY, maxX, maxY of the drawable area
+	yield   func(RasterFragment, error) bool
+}
+
+// project applies the perspective divide and viewport transform.
+func (s *raster) project(v clipVertex) screenVertex {
+	invW := 1 / v.pos[3]
+	return screenVertex{
+		x:    s.vp.X + (v.pos[0]*invW+1)*0.5*s.vp.Width,
+		y:    s.vp.Y + (1-v.pos[1]*invW)*0.5*s.vp.Height,
+		z:    v.pos[2] * invW,
+		invW: invW,
+		bary: v.bary,
+	}
+}
+
+// sampleDepth maps an NDC depth to the viewport depth range, adds bias and
+// clamps. ok is false if the sample is outside the depth clip volume.
+func (s *raster) sampleDepth(z, bias float64) (depth float64, ok bool) {
+	if !s.r.Primitive.UnclippedDepth && (z < 0 || z > 1) {
+		return 0, false
+	}
+	d := s.vp.MinDepth + z*(s.vp.MaxDepth-s.vp.MinDepth) + bias
+	return min(max(d, min(s.vp.MinDepth, s.vp.MaxDepth)), max(s.vp.MinDepth, s.vp.MaxDepth)), true
+}
+
+// finish applies the sample mask to frag's coverage and stores the depths
+// of the samples that remain. It returns false if no sample is covered.
+func (s *raster) finish(frag *RasterFragment, depths []float64) bool {
+	if frag.Coverage &= s.mask; frag.Coverage == 0 {
+		return false
+	}
+	for i := range depths {
+		if frag.Coverage&(1<<i) == 0 {
+			depths[i] = 0
+		}
+	}
+	frag.SampleDepth = depths
+	return true
+}
+
+// centerDepth returns the depth at a pixel center, which is interpolated
+// even when the center lies outside the primitive or depth clip volume.
+func (s *raster) centerDepth(z, bias float64) float64 {
+	if !s.r.Primitive.UnclippedDepth {
+		z = min(max(z, 0), 1)
+	}
+	d, _ := s.sampleDepth(z, bias)
+	return d
+}
+
+// inBounds reports whether a sample position is inside the drawable area.
+func (s *raster) inBounds(x, y float64) bool {
+	return x >= s.bounds[0] && y >= s.bounds[1] && x < s.bounds[2] && y < s.bounds[3]
+}
+
+// pixels calls visit for every pixel whose square overlaps the box,
+// clamped to the drawable area, in row-major order.
+func (s *raster) pixels(minX, minY, maxX, maxY float64, visit func(px, py uint32) bool) bool {
+	x0 := math.Floor(max(minX, s.bounds[0]))
+	y0 := math.Floor(max(minY, s.bounds[1]))
+	x1 := math.Ceil(min(maxX, s.bounds[2]))
+	y1 := math.Ceil(min(maxY, s.bounds[3]))
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			if !visit(uint32(px), uint32(py)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// edge returns twice the signed area of (a, b, p); positive when p is
+// clockwise from a->b in framebuffer coordinates (y down).
+func edge(ax, ay, bx, by, px, py float64) float64 {
+	return (bx-ax)*(py-ay) - (by-ay)*(px-ax)
+}
+
+// isTopLeft reports whether the edge a->b of a clockwise (y down) triangle
+// is a top or left edge, which owns samples exactly on it.
+func isTopLeft(a, b screenVertex) bool {
+	dx, dy := b.x-a.x, b.y-a.y
+	return (dy == 0 && dx > 0) || dy < 0
+}
+
+// triangle clips and rasterizes one triangle. It returns false if the
+// consumer stopped iteration.
+func (s *raster) triangle(prim int, idx [3]int, pos [3][4]float64) bool {
+	poly := clipW([]clipVertex{
+		{pos[0], [3]float64{1, 0, 0}},
+		{pos[1], [3]float64{0, 1, 0}},
+		{pos[2], [3]float64{0, 0, 1}},
+	})
+	for k := 1; k+1 < len(poly); k++ {
+		v := [3]screenVertex{s.project(poly[0]), s.project(poly[k]), s.project(poly[k+1])}
+		if !s.rasterTriangle(prim, idx, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// rasterTriangle rasterizes one projected triangle.
+func (s *raster) rasterTriangle(prim int, idx [3]int, v [3]screenVertex) bool {
+	area := edge(v[0].x, v[0].y, v[1].x, v[1].y, v[2].x, v[2].y)
+	if area == 0 || math.IsNaN(area) {
+		return true
+	}
+	// Counter-clockwise in NDC (y up) is clockwise, negative area, here.
+	front := s.r.Primitive.FrontFace.IsFrontFacing(area < 0)
+	switch s.r.Primitive.CullMode {
+	case CullModeFront:
+		if front {
+			return true
+		}
+	case CullModeBack:
+		if !front {
+			return true
+		}
+	}
+	if area < 0 {
+		v[1], v[2] = v[2], v[1]
+		area = -area
+	}
+	bias := s.depthBias(v, area)
+
+	weights := func(x, y float64) [3]float64 {
+		return [3]float64{
+			edge(v[1].x, v[1].y, v[2].x, v[2].y, x, y) / area,
+			edge(v[2].x, v[2].y, v[0].x, v[0].y, x, y) / area,
+			edge(v[0].x, v[0].y, v[1].x, v[1].y, x, y) / area,
+		}
+	}
+	topLeft := [3]bool{isTopLeft(v[1], v[2]), isTopLeft(v[2], v[0]), isTopLeft(v[0], v[1])}
+
+	minX := min(v[0].x, v[1].x, v[2].x)
+	minY := min(v[0].y, v[1].y, v[2].y)
+	maxX := max(v[0].x, v[1].x, v[2].x)
+	maxY := max(v[0].y, v[1].y, v[2].y)
+	return s.pixels(minX, minY, maxX, maxY, func(px, py uint32) bool {
+		frag := RasterFragment{X: px, Y: py, Primitive: prim, Vertices: idx, FrontFacing: front}
+		depths := make([]float64, len(s.samples))
+		for i, sp := range s.samples {
+			x, y := float64(px)+sp[0], float64(py)+sp[1]
+			if !s.inBounds(x, y) {
+				continue
+			}
+			l := weights(x, y)
+			inside := true
+			for e := range 3 {
+				if l[e] < 0 || (l[e] == 0 && !topLeft[e]) {
+					inside = false
+					break
+				}
+			}
+			if !inside {
+				continue
+			}
+			d, ok := s.sampleDepth(l[0]*v[0].z+l[1]*v[1].z+l[2]*v[2].z, bias)
+			if !ok {
+				continue
+			}
+			frag.Coverage |= 1 << i
+			depths[i] = d
+		}
+		if !s.finish(&frag, depths) {
+			return true
+		}
+
+		l := weights(float64(px)+0.5, float64(py)+0.5)
+		frag.Depth = s.centerDepth(l[0]*v[0].z+l[1]*v[1].z+l[2]*v[2].z, bias)
+		frag.LinearBarycentric, frag.Barycentric = interpolateBary(v[:], l[:])
+		return s.yield(frag, nil)
+	})
+}
+
+// interpolateBary combines the original-primitive barycentrics of the
+// projected vertices with screen-space weights l, returning the
+// screen-linear and perspective-correct results.
+func interpolateBary(v []screenVertex, l []float64) (linear, perspective [3]float64) {
+	var q float64
+	for i := range v {
+		q += l[i] * v[i].invW
+	}
+	for i := range v {
+		pw := l[i] * v[i].invW / q
+		for k := range 3 {
+			linear[k] += l[i] * v[i].bary[k]
+			perspective[k] += pw * v[i].bary[k]
+		}
+	}
+	return linear, perspective
+}
+
+// depthBias returns the depth bias of a triangle in depth buffer units:
+// DepthBias * r + DepthBiasSlopeScale * maxSlope, clamped by DepthBiasClamp,
+// where r is the minimum resolvable difference of the depth format.
+func (s *raster) depthBias(v [3]screenVertex, area float64) float64 {
+	ds := s.r.DepthStencil
+	if ds == nil || (ds.DepthBias == 0 && ds.DepthBiasSlopeScale == 0) {
+		return 0
+	}
+	scale := s.vp.MaxDepth - s.vp.MinDepth
+	var z [3]float64
+	for i := range v {
+		z[i] = s.vp.MinDepth + v[i].z*scale
+	}
+	// Plane z = a*x + b*y + c through the three vertices.
+	a := ((z[1]-z[0])*(v[2].y-v[0].y) - (z[2]-z[0])*(v[1].y-v[0].y)) / area
+	b := ((v[1].x-v[0].x)*(z[2]-z[0]) - (v[2].x-v[0].x)*(z[1]-z[0])) / area
+	maxSlope := max(math.Abs(a), math.Abs(b))
+
+	var r float64
+	switch ds.Format {
+	case TextureFormatDepth16Unorm:
+		r = 1.0 / (1 << 16)
+	case TextureFormatDepth24Plus, TextureFormatDepth24PlusStencil8:
+		r = 1.0 / (1 << 24)
+	case TextureFormatDepth32Float, TextureFormatDepth32FloatStencil8:
+		_, e := math.Frexp(max(math.Abs(z[0]), math.Abs(z[1]), math.Abs(z[2])))
+		r = math.Ldexp(1, e-1-23)
+	}
+
+	bias := float64(ds.DepthBias)*r + float64(ds.DepthBiasSlopeScale)*maxSlope
+	switch clamp := float64(ds.DepthBiasClamp); {
+	case clamp > 0:
+		bias = min(bias, clamp)
+	case clamp < 0:
+		bias = max(bias, clamp)
+	}
+	return bias
+}
+
+// line clips and rasterizes one line segment.
+func (s *raster) line(prim int, idx [2]int, pos [2][4]float64) bool {
+	a := clipVertex{pos[0], [3]float64{1, 0, 0}}
+	b := clipVertex{pos[1], [3]float64{0, 1, 0}}
+	aIn, bIn := a.pos[3] >= wClipEpsilon, b.pos[3] >= wClipEpsilon
+	switch {
+	case !aIn && !bIn:
+		return true
+	case !aIn:
+		a = a.lerp(b, (wClipEpsilon-a.pos[3])/(b.pos[3]-a.pos[3]))
+	case !bIn:
+		b = a.lerp(b, (wClipEpsilon-a.pos[3])/(b.pos[3]-a.pos[3]))
+	}
+	v := [2]screenVertex{s.project(a), s.project(b)}
+
+	dx, dy := v[1].x-v[0].x, v[1].y-v[0].y
+	length := math.Hypot(dx, dy)
+	if length == 0 || math.IsNaN(length) {
+		return true
+	}
+	ux, uy := dx/length, dy/length
+	// along returns the distance along the segment and across it.
+	along := func(x, y float64) (t, n float64) {
+		px, py := x-v[0].x, y-v[0].y
+		return px*ux + py*uy, px*-uy + py*ux
+	}
+
+	vertices := [3]int{idx[0], idx[1], -1}
+	return s.pixels(min(v[0].x, v[1].x)-0.5, min(v[0].y, v[1].y)-0.5, max(v[0].x, v[1].x)+0.5, max(v[0].y, v[1].y)+0.5,
+		func(px, py uint32) bool {
+			frag := RasterFragment{X: px, Y: py, Primitive: prim, Vertices: vertices, FrontFacing: true}
+			depths := make([]float64, len(s.samples))
+			for i, sp := range s.samples {
+				x, y := float64(px)+sp[0], float64(py)+sp[1]
+				if !s.inBounds(x, y) {
+					continue
+				}
+				t, n := along(x, y)
+				if t < 0 || t >= length || n < -0.5 || n >= 0.5 {
+					continue
+				}
+				f := t / length
+				d, ok := s.sampleDepth(v[0].z+(v[1].z-v[0].z)*f, 0)
+				if !ok {
+					continue
+				}
+				frag.Coverage |= 1 << i
+				depths[i] = d
+			}
+			if !s.finish(&frag, depths) {
+				return true
+			}
+
+			t, _ := along(float64(px)+0.5, float64(py)+0.5)
+			f := min(max(t/length, 0), 1)
+			frag.Depth = s.centerDepth(v[0].z+(v[1].z-v[0].z)*f, 0)
+			frag.LinearBarycentric, frag.Barycentric = interpolateBary(v[:], []float64{1 - f, f})
+			return s.yield(frag, nil)
+		})
+}
+
+// point rasterizes one point as a 1-pixel square.
+func (s *raster) point(prim, idx int, pos [4]float64) bool {
+	if pos[3] < wClipEpsilon {
+		return true
+	}
+	v := s.project(clipVertex{pos: pos, bary: [3]float64{1, 0, 0}})
+	depth, ok := s.sampleDepth(v.z, 0)
+	if !ok {
+		return true
+	}
+	return s.pixels(v.x-0.5, v.y-0.5, v.x+0.5, v.y+0.5, func(px, py uint32) bool {
+		frag := RasterFragment{
+			X: px, Y: py, Primitive: prim, Vertices: [3]int{idx, -1, -1}, FrontFacing: true,
+			Barycentric: [3]float64{1, 0, 0}, LinearBarycentric: [3]float64{1, 0, 0}, Depth: depth,
+		}
+		depths := make([]float64, len(s.samples))
+		for i, sp := range s.samples {
+			x, y := float64(px)+sp[0], float64(py)+sp[1]
+			if s.inBounds(x, y) && x >= v.x-0.5 && x < v.x+0.5 && y >= v.y-0.5 && y < v.y+0.5 {
+				frag.Coverage |= 1 << i
+				depths[i] = depth
+			}
+		}
+		if !s.finish(&frag, depths) {
+			return true
+		}
+		return s.yield(frag, nil)
+	})
+}
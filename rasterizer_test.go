@@ -0,0 +1,226 @@
+package gputypes
+
+import (
+	"math"
+	"testing"
+)
+
+// ndc returns the clip-space position of a framebuffer point on a 4x4
+// target with the default viewport.
+func ndc(x, y, z float64) [4]float64 {
+	return [4]float64{x/2 - 1, 1 - y/2, z, 1}
+}
+
+func rasterize(t *testing.T, r *Rasterizer, positions [][4]float64) []RasterFragment {
+	t.Helper()
+	var frags []RasterFragment
+	for f, err := range r.Rasterize(positions) {
+		if err != nil {
+			t.Fatalf("Rasterize() error = %v", err)
+		}
+		frags = append(frags, f)
+	}
+	return frags
+}
+
+func TestStandardSamplePositions(t *testing.T) {
+	for _, n := range []uint32{1, 2, 4, 8, 16} {
+		pos := StandardSamplePositions(n)
+		if len(pos) != int(n) {
+			t.Fatalf("StandardSamplePositions(%d) has %d positions", n, len(pos))
+		}
+		for _, p := range pos {
+			if p[0] < 0 || p[0] >= 1 || p[1] < 0 || p[1] >= 1 {
+				t.Errorf("StandardSamplePositions(%d) position %v outside pixel", n, p)
+			}
+		}
+	}
+	if got := StandardSamplePositions(4)[0]; got != [2]float64{0.375, 0.125} {
+		t.Errorf("4x sample 0 = %v, want [0.375 0.125]", got)
+	}
+	if StandardSamplePositions(3) != nil {
+		t.Error("StandardSamplePositions(3) != nil")
+	}
+}
+
+func TestRasterizer_TopLeftRule(t *testing.T) {
+	// A quad split along the diagonal through every pixel center: each
+	// pixel must be drawn exactly once.
+	r := &Rasterizer{Width: 4, Height: 4}
+	quad := [][4]float64{
+		ndc(0, 0, 0), ndc(4, 0, 0), ndc(4, 4, 0),
+		ndc(0, 0, 0), ndc(4, 4, 0), ndc(0, 4, 0),
+	}
+	hits := map[[2]uint32]int{}
+	for _, f := range rasterize(t, r, quad) {
+		hits[[2]uint32{f.X, f.Y}]++
+	}
+	if len(hits) != 16 {
+		t.Errorf("covered %d pixels, want 16", len(hits))
+	}
+	for p, n := range hits {
+		if n != 1 {
+			t.Errorf("pixel %v drawn %d times", p, n)
+		}
+	}
+}
+
+func TestRasterizer_Culling(t *testing.T) {
+	ccw := [][4]float64{ndc(0, 4, 0), ndc(4, 4, 0), ndc(0, 0, 0)} // counter-clockwise in NDC
+	cw := [][4]float64{ccw[0], ccw[2], ccw[1]}
+
+	tests := []struct {
+		name      string
+		primitive PrimitiveState
+		positions [][4]float64
+		visible   bool
+		front     bool
+	}{
+		{"ccw front, no cull", PrimitiveState{}, ccw, true, true},
+		{"cw back, no cull", PrimitiveState{}, cw, true, false},
+		{"cull back keeps ccw", PrimitiveState{CullMode: CullModeBack}, ccw, true, true},
+		{"cull back drops cw", PrimitiveState{CullMode: CullModeBack}, cw, false, false},
+		{"cull front drops ccw", PrimitiveState{CullMode: CullModeFront}, ccw, false, false},
+		{"front face cw", PrimitiveState{FrontFace: FrontFaceCW, CullMode: CullModeBack}, cw, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frags := rasterize(t, &Rasterizer{Primitive: tt.primitive, Width: 4, Height: 4}, tt.positions)
+			if (len(frags) > 0) != tt.visible {
+				t.Fatalf("got %d fragments, visible = %v", len(frags), tt.visible)
+			}
+			if tt.visible && frags[0].FrontFacing != tt.front {
+				t.Errorf("FrontFacing = %v, want %v", frags[0].FrontFacing, tt.front)
+			}
+		})
+	}
+}
+
+func TestRasterizer_Multisample(t *testing.T) {
+	// Left half of the target: x < 2.5 covers 4x samples at x offsets
+	// 0.375 and 0.125 of pixel 2 (samples 0 and 2), but not 0.875 or 0.625.
+	tri := [][4]float64{ndc(0, -4, 0), ndc(2.5, -4, 0), ndc(2.5, 12, 0)}
+	r := &Rasterizer{Width: 4, Height: 4, Multisample: MultisampleState{Count: 4}}
+	quad := append(tri, ndc(0, -4, 0), ndc(2.5, 12, 0), ndc(0, 12, 0))
+
+	cov := map[[2]uint32]uint32{}
+	for _, f := range rasterize(t, r, quad) {
+		cov[[2]uint32{f.X, f.Y}] |= f.Coverage
+	}
+	for y := range uint32(4) {
+		if cov[[2]uint32{0, y}] != 0xF || cov[[2]uint32{2, y}] != 0b0101 {
+			t.Errorf("row %d coverage = %04b %04b, want 1111 0101", y, cov[[2]uint32{0, y}], cov[[2]uint32{2, y}])
+		}
+	}
+
+	r.Multisample.Mask = 0b0100
+	for _, f := range rasterize(t, r, quad) {
+		if f.Coverage != 0b0100 {
+			t.Errorf("masked coverage = %04b, want 0100", f.Coverage)
+		}
+	}
+
+	for _, err := range (&Rasterizer{Multisample: MultisampleState{Count: 3}}).Rasterize(tri) {
+		if err == nil {
+			t.Error("sample count 3 accepted")
+		}
+	}
+}
+
+func TestRasterizer_Barycentrics(t *testing.T) {
+	r := &Rasterizer{Width: 4, Height: 4}
+	// The same screen triangle, with the far vertex at w = 4.
+	far := ndc(0, 0, 0.5)
+	for i := range far {
+		far[i] *= 4
+	}
+	tri := [][4]float64{ndc(0, 4, 0.5), ndc(4, 4, 0.5), far}
+
+	for _, f := range rasterize(t, r, tri) {
+		var sum, lsum float64
+		for i := range 3 {
+			sum += f.Barycentric[i]
+			lsum += f.LinearBarycentric[i]
+		}
+		if math.Abs(sum-1) > 1e-9 || math.Abs(lsum-1) > 1e-9 {
+			t.Fatalf("barycentrics do not sum to 1: %v %v", f.Barycentric, f.LinearBarycentric)
+		}
+		if f.Barycentric[2] >= f.LinearBarycentric[2] {
+			t.Errorf("pixel %d,%d: perspective weight of far vertex %v not below linear %v",
+				f.X, f.Y, f.Barycentric[2], f.LinearBarycentric[2])
+		}
+		if math.Abs(f.Depth-0.5) > 1e-9 {
+			t.Errorf("Depth = %v, want 0.5", f.Depth)
+		}
+	}
+}
+
+func TestRasterizer_Depth(t *testing.T) {
+	tri := [][4]float64{ndc(0, 4, 0.25), ndc(4, 4, 0.25), ndc(0, 0, 0.25)}
+
+	ds := DefaultDepthStencilState(TextureFormatDepth24Plus)
+	ds.DepthBias = 4
+	r := &Rasterizer{Width: 4, Height: 4, DepthStencil: &ds,
+		Viewport: Viewport{Width: 4, Height: 4, MinDepth: 0.5, MaxDepth: 1}}
+	frags := rasterize(t, r, tri)
+	if len(frags) == 0 {
+		t.Fatal("no fragments")
+	}
+	want := 0.625 + 4.0/(1<<24)
+	if math.Abs(frags[0].Depth-want) > 1e-12 || math.Abs(frags[0].SampleDepth[0]-want) > 1e-12 {
+		t.Errorf("Depth = %v, SampleDepth = %v, want %v", frags[0].Depth, frags[0].SampleDepth, want)
+	}
+
+	ds.DepthBiasClamp = 1.0 / (1 << 24)
+	if got := rasterize(t, r, tri)[0].Depth; math.Abs(got-(0.625+float64(ds.DepthBiasClamp))) > 1e-12 {
+		t.Errorf("clamped Depth = %v", got)
+	}
+
+	clipped := [][4]float64{ndc(0, 4, 2), ndc(4, 4, 2), ndc(0, 0, 2)}
+	if n := len(rasterize(t, &Rasterizer{Width: 4, Height: 4}, clipped)); n != 0 {
+		t.Errorf("triangle beyond far plane produced %d fragments", n)
+	}
+	unclipped := &Rasterizer{Width: 4, Height: 4, Primitive: PrimitiveState{UnclippedDepth: true}}
+	frags = rasterize(t, unclipped, clipped)
+	if len(frags) == 0 || frags[0].Depth != 1 {
+		t.Errorf("unclipped depth fragments = %d, want depth clamped to 1", len(frags))
+	}
+}
+
+func TestRasterizer_ClipsBehindCamera(t *testing.T) {
+	tri := [][4]float64{{-1, -1, 0.5, 1}, {1, -1, 0.5, 1}, {0, 3, -0.5, -1}}
+	frags := rasterize(t, &Rasterizer{Width: 4, Height: 4}, tri)
+	if len(frags) == 0 {
+		t.Fatal("partially visible triangle produced no fragments")
+	}
+	for _, f := range frags {
+		for _, b := range f.Barycentric {
+			if math.IsNaN(b) || math.IsInf(b, 0) {
+				t.Fatalf("invalid barycentric %v", f.Barycentric)
+			}
+		}
+	}
+}
+
+func TestRasterizer_LinesAndPoints(t *testing.T) {
+	line := [][4]float64{ndc(0.5, 0.5, 0), ndc(3.5, 0.5, 0)}
+	r := &Rasterizer{Width: 4, Height: 4, Primitive: PrimitiveState{Topology: PrimitiveTopologyLineList}}
+	frags := rasterize(t, r, line)
+	if len(frags) != 3 {
+		t.Fatalf("line covered %d pixels, want 3", len(frags))
+	}
+	for i, f := range frags {
+		if f.X != uint32(i) || f.Y != 0 || f.Vertices != [3]int{0, 1, -1} {
+			t.Errorf("fragment %d = (%d, %d) %v", i, f.X, f.Y, f.Vertices)
+		}
+		if want := float64(i) / 3; math.Abs(f.Barycentric[1]-want) > 1e-9 {
+			t.Errorf("fragment %d weight = %v, want %v", i, f.Barycentric[1], want)
+		}
+	}
+
+	r.Primitive.Topology = PrimitiveTopologyPointList
+	frags = rasterize(t, r, [][4]float64{ndc(1.5, 2.5, 0), ndc(1, 1, 0)})
+	if len(frags) != 2 || frags[0].X != 1 || frags[0].Y != 2 || frags[1].X != 0 || frags[1].Y != 0 || frags[1].Primitive != 1 {
+		t.Errorf("points = %+v", frags)
+	}
+}
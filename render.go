@@ -396,9 +396,9 @@ func DefaultPrimitiveState() PrimitiveState {
 
 // MultisampleState describes multisampling state.
 type MultisampleState struct {
-	// Count is the number of samples per pixel (1, 2, 4, 8, or 16).
+	// Count is the number of samples per pixel (1, 2, 4, 8, or 16; 0 means 1).
 	Count uint32
-	// Mask is the sample mask (all bits set = all samples).
+	// Mask is the sample mask (all bits set = all samples; 0 means all samples).
 	Mask uint64
 	// AlphaToCoverageEnabled enables alpha-to-coverage.
 	AlphaToCoverageEnabled bool
@@ -529,6 +529,9 @@ func DefaultDepthStencilState(format TextureFormat) DepthStencilState {
 type RenderPassColorAttachment struct {
 	// View is the texture view to render to (implementation-specific handle).
 	View uintptr
+	// DepthSlice is the slice of a 3D View to render to. It must be 0 for
+	// other view dimensions.
+	DepthSlice uint32
 	// ResolveTarget is the texture view for multisample resolve (0 if none).
 	ResolveTarget uintptr
 	// LoadOp describes how to load the attachment.
@@ -569,4 +572,33 @@ type RenderPassDescriptor struct {
 	ColorAttachments []RenderPassColorAttachment
 	// DepthStencilAttachment is the depth-stencil attachment (nil if none).
 	DepthStencilAttachment *RenderPassDepthStencilAttachment
+	// OcclusionQuerySet is the occlusion query set (0 if none). The handle
+	// is implementation-specific; Validate does not check that it is a
+	// QueryTypeOcclusion set.
+	OcclusionQuerySet uintptr
+	// TimestampWrites are the timestamps to write at the beginning and end
+	// of the pass (nil if none).
+	TimestampWrites *PassTimestampWrites
+	// MaxDrawCount is the maximum number of draw calls in the pass
+	// (0 means DefaultMaxDrawCount).
+	MaxDrawCount uint64
+}
+
+// DefaultMaxDrawCount is the MaxDrawCount used when a RenderPassDescriptor leaves it 0.
+const DefaultMaxDrawCount uint64 = 50_000_000
+
+// QuerySetIndexUndefined marks a PassTimestampWrites index as unused.
+const QuerySetIndexUndefined uint32 = 0xFFFFFFFF
+
+// PassTimestampWrites describes the timestamps a render or compute pass
+// writes into a timestamp query set.
+type PassTimestampWrites struct {
+	// QuerySet is the timestamp query set (implementation-specific handle).
+	QuerySet uintptr
+	// BeginningOfPassWriteIndex is the query written at the start of the
+	// pass, or QuerySetIndexUndefined.
+	BeginningOfPassWriteIndex uint32
+	// EndOfPassWriteIndex is the query written at the end of the pass, or
+	// QuerySetIndexUndefined.
+	EndOfPassWriteIndex uint32
 }
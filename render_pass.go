@@ -0,0 +1,401 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Render pass validation errors.
+//
+// RenderPassDescriptor.Validate wraps these in a *RenderPassError that
+// records which attachment the problem was found in; test for them with
+// errors.Is.
+var (
+	// ErrNoAttachments means the pass has neither color nor depth/stencil attachments.
+	ErrNoAttachments = errors.New("render pass has no attachments")
+	// ErrTooManyColorAttachments means more color attachments than Limits.MaxColorAttachments.
+	ErrTooManyColorAttachments = errors.New("too many color attachments")
+	// ErrAttachmentView means the view handle is zero or unknown to the resolver.
+	ErrAttachmentView = errors.New("invalid attachment view")
+	// ErrAttachmentFormat means the view format cannot be used for the attachment.
+	ErrAttachmentFormat = errors.New("invalid attachment format")
+	// ErrAttachmentUsage means the view's texture lacks TextureUsageRenderAttachment.
+	ErrAttachmentUsage = errors.New("attachment texture lacks RenderAttachment usage")
+	// ErrAttachmentSize means the attachments do not all have the same size.
+	ErrAttachmentSize = errors.New("attachment size mismatch")
+	// ErrAttachmentSampleCount means the attachments do not all have the same sample count.
+	ErrAttachmentSampleCount = errors.New("attachment sample count mismatch")
+	// ErrResolveTarget means the resolve target is incompatible with its attachment.
+	ErrResolveTarget = errors.New("invalid resolve target")
+	// ErrLoadOp means a load operation is undefined where it is required, or set where it is not allowed.
+	ErrLoadOp = errors.New("invalid load operation")
+	// ErrStoreOp means a store operation is undefined where it is required, or set where it is not allowed.
+	ErrStoreOp = errors.New("invalid store operation")
+	// ErrClearValue means a clear value is out of range for the attachment format.
+	ErrClearValue = errors.New("clear value out of range")
+	// ErrDepthSlice means DepthSlice is out of range, set on a non-3D view, or
+	// the same view slice is used by two color attachments.
+	ErrDepthSlice = errors.New("invalid depth slice")
+	// ErrTimestampWrites means the pass timestamp writes are malformed.
+	ErrTimestampWrites = errors.New("invalid timestamp writes")
+)
+
+// AttachmentViewInfo describes the texture view behind an attachment
+// handle, as returned by an AttachmentViewResolver.
+type AttachmentViewInfo struct {
+	// Format is the view format.
+	Format TextureFormat
+	// Dimension is the view dimension (Undefined is read as 2D).
+	Dimension TextureViewDimension
+	// Width and Height are the size of the viewed mip level.
+	Width, Height uint32
+	// Depth is the depth of the viewed mip level of a 3D view.
+	Depth uint32
+	// SampleCount is the texture sample count (0 is read as 1).
+	SampleCount uint32
+	// Usage is the usage of the viewed texture.
+	Usage TextureUsage
+}
+
+// sampleCount returns SampleCount with 0 read as 1.
+func (v AttachmentViewInfo) sampleCount() uint32 {
+	return max(v.SampleCount, 1)
+}
+
+// AttachmentViewResolver maps an implementation-specific view handle to
+// its description. ok is false for unknown handles.
+type AttachmentViewResolver func(view uintptr) (info AttachmentViewInfo, ok bool)
+
+// RenderPassError reports an invalid render pass attachment and where it was found.
+type RenderPassError struct {
+	// Color is the index into ColorAttachments, or -1 if the error does not
+	// concern a color attachment.
+	Color int
+	// DepthStencil is true if the error concerns the depth/stencil attachment.
+	DepthStencil bool
+	// Err describes the problem and wraps one of the Err* sentinels.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RenderPassError) Error() string {
+	switch {
+	case e.Color >= 0:
+		return fmt.Sprintf("gputypes: render pass color attachment %d: %v", e.Color, e.Err)
+	case e.DepthStencil:
+		return fmt.Sprintf("gputypes: render pass depth/stencil attachment: %v", e.Err)
+	default:
+		return fmt.Sprintf("gputypes: render pass: %v", e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *RenderPassError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the attachments against the WebGPU rules and the given
+// limits, using resolve to describe each view, and returns the first
+// violation as a *RenderPassError.
+//
+// It checks that:
+//   - there is at least one attachment and at most MaxColorAttachments
+//     color attachments, fitting MaxColorAttachmentBytesPerSample
+//   - every view is known, has RenderAttachment usage and a suitable format
+//   - all attachments have the same size and sample count
+//   - a ResolveTarget is single-sampled, resolves a multisampled view, and
+//     has the same format and size
+//   - color load and store operations are defined
+//   - depth and stencil operations are defined exactly when the format has
+//     that aspect and it is not read-only
+//   - integer color clear values are representable in the format, and the
+//     depth clear value is in [0, 1]
+//   - DepthSlice is in range for 3D views, 0 otherwise, and no view slice
+//     is used twice
+//   - TimestampWrites, if set, are well formed (see PassTimestampWrites.Validate)
+//
+// Color attachments with a zero View are unused slots and are skipped.
+// OcclusionQuerySet is not checked, and neither are query set types, sizes
+// or the timestamp feature; the resolver only describes views.
+func (d *RenderPassDescriptor) Validate(limits Limits, resolve AttachmentViewResolver) error {
+	passErr := func(err error) error {
+		return &RenderPassError{Color: -1, Err: err}
+	}
+	if len(d.ColorAttachments) > int(limits.MaxColorAttachments) {
+		return passErr(fmt.Errorf("%w: %d > %d", ErrTooManyColorAttachments, len(d.ColorAttachments), limits.MaxColorAttachments))
+	}
+
+	var (
+		first      *AttachmentViewInfo
+		formats    []TextureFormat
+		seenSlices = make(map[[2]uintptr]bool)
+	)
+	// matches checks that v has the same size and sample count as the first attachment.
+	matches := func(v AttachmentViewInfo) error {
+		if first == nil {
+			first = &v
+			return nil
+		}
+		if v.Width != first.Width || v.Height != first.Height {
+			return fmt.Errorf("%w: %dx%d, want %dx%d", ErrAttachmentSize, v.Width, v.Height, first.Width, first.Height)
+		}
+		if v.sampleCount() != first.sampleCount() {
+			return fmt.Errorf("%w: %d, want %d", ErrAttachmentSampleCount, v.sampleCount(), first.sampleCount())
+		}
+		return nil
+	}
+
+	for i, a := range d.ColorAttachments {
+		if a.View == 0 {
+			continue
+		}
+		v, err := a.validate(resolve)
+		if err == nil {
+			err = matches(v)
+		}
+		if err == nil {
+			key := [2]uintptr{a.View, uintptr(a.DepthSlice)}
+			if seenSlices[key] {
+				err = fmt.Errorf("%w: view %#x slice %d is already an attachment", ErrDepthSlice, a.View, a.DepthSlice)
+			}
+			seenSlices[key] = true
+		}
+		if err != nil {
+			return &RenderPassError{Color: i, Err: err}
+		}
+		formats = append(formats, v.Format)
+	}
+	if n := ColorAttachmentBytesPerSample(formats...); n > limits.MaxColorAttachmentBytesPerSample {
+		return passErr(fmt.Errorf("%w: %d > %d", ErrColorAttachmentBytesPerSample, n, limits.MaxColorAttachmentBytesPerSample))
+	}
+
+	if ds := d.DepthStencilAttachment; ds != nil {
+		v, err := ds.validate(resolve)
+		if err == nil {
+			err = matches(v)
+		}
+		if err != nil {
+			return &RenderPassError{Color: -1, DepthStencil: true, Err: err}
+		}
+	}
+
+	if first == nil {
+		return passErr(ErrNoAttachments)
+	}
+	if w := d.TimestampWrites; w != nil {
+		if err := w.validate(QuerySetIndexUndefined); err != nil {
+			return passErr(err)
+		}
+	}
+	return nil
+}
+
+// DrawCountLimit returns MaxDrawCount, or DefaultMaxDrawCount if it is 0.
+func (d *RenderPassDescriptor) DrawCountLimit() uint64 {
+	if d.MaxDrawCount == 0 {
+		return DefaultMaxDrawCount
+	}
+	return d.MaxDrawCount
+}
+
+// Validate checks that the timestamp writes name a query set, write at
+// least one timestamp, use distinct indices, and fit a query set of
+// querySetCount queries.
+func (w *PassTimestampWrites) Validate(querySetCount uint32) error {
+	if err := w.validate(querySetCount); err != nil {
+		return fmt.Errorf("gputypes: %w", err)
+	}
+	return nil
+}
+
+// validate implements Validate without the package prefix.
+func (w *PassTimestampWrites) validate(querySetCount uint32) error {
+	begin, end := w.BeginningOfPassWriteIndex, w.EndOfPassWriteIndex
+	switch {
+	case w.QuerySet == 0:
+		return fmt.Errorf("%w: no query set", ErrTimestampWrites)
+	case begin == QuerySetIndexUndefined && end == QuerySetIndexUndefined:
+		return fmt.Errorf("%w: no indices", ErrTimestampWrites)
+	case begin == end:
+		return fmt.Errorf("%w: beginning and end both write index %d", ErrTimestampWrites, begin)
+	}
+	for _, i := range [2]uint32{begin, end} {
+		if i != QuerySetIndexUndefined && i >= querySetCount {
+			return fmt.Errorf("%w: index %d out of range for %d queries", ErrTimestampWrites, i, querySetCount)
+		}
+	}
+	return nil
+}
+
+// resolveView looks up an attachment view and checks its usage.
+func resolveView(resolve AttachmentViewResolver, view uintptr) (AttachmentViewInfo, error) {
+	if view == 0 {
+		return AttachmentViewInfo{}, fmt.Errorf("%w: zero handle", ErrAttachmentView)
+	}
+	v, ok := resolve(view)
+	if !ok {
+		return AttachmentViewInfo{}, fmt.Errorf("%w: unknown handle %#x", ErrAttachmentView, view)
+	}
+	if !v.Usage.Contains(TextureUsageRenderAttachment) {
+		return AttachmentViewInfo{}, ErrAttachmentUsage
+	}
+	return v, nil
+}
+
+// validate checks a color attachment on its own and returns its view.
+func (a *RenderPassColorAttachment) validate(resolve AttachmentViewResolver) (AttachmentViewInfo, error) {
+	v, err := resolveView(resolve, a.View)
+	if err != nil {
+		return v, err
+	}
+	if !v.Format.IsColorRenderable() {
+		return v, fmt.Errorf("%w: %s is not color renderable", ErrAttachmentFormat, v.Format)
+	}
+	if v.Dimension == TextureViewDimension3D {
+		if a.DepthSlice >= v.Depth {
+			return v, fmt.Errorf("%w: %d >= depth %d", ErrDepthSlice, a.DepthSlice, v.Depth)
+		}
+	} else if a.DepthSlice != 0 {
+		return v, fmt.Errorf("%w: %d on %s view", ErrDepthSlice, a.DepthSlice, v.Dimension)
+	}
+
+	switch a.LoadOp {
+	case LoadOpLoad:
+	case LoadOpClear:
+		if err := checkColorClearValue(v.Format, a.ClearValue); err != nil {
+			return v, err
+		}
+	default:
+		return v, fmt.Errorf("%w: %s", ErrLoadOp, a.LoadOp)
+	}
+	if a.StoreOp != StoreOpStore && a.StoreOp != StoreOpDiscard {
+		return v, fmt.Errorf("%w: %s", ErrStoreOp, a.StoreOp)
+	}
+
+	if a.ResolveTarget != 0 {
+		r, err := resolveView(resolve, a.ResolveTarget)
+		if err != nil {
+			return v, fmt.Errorf("%w: %w", ErrResolveTarget, err)
+		}
+		switch {
+		case v.sampleCount() == 1:
+			return v, fmt.Errorf("%w: attachment is not multisampled", ErrResolveTarget)
+		case r.sampleCount() != 1:
+			return v, fmt.Errorf("%w: sample count %d, want 1", ErrResolveTarget, r.sampleCount())
+		case r.Format != v.Format:
+			return v, fmt.Errorf("%w: format %s, want %s", ErrResolveTarget, r.Format, v.Format)
+		case r.Width != v.Width || r.Height != v.Height:
+			return v, fmt.Errorf("%w: size %dx%d, want %dx%d", ErrResolveTarget, r.Width, r.Height, v.Width, v.Height)
+		case !v.Format.IsBlendable():
+			return v, fmt.Errorf("%w: %s cannot be resolved", ErrResolveTarget, v.Format)
+		}
+	}
+	return v, nil
+}
+
+// validate checks the depth/stencil attachment on its own and returns its view.
+func (a *RenderPassDepthStencilAttachment) validate(resolve AttachmentViewResolver) (AttachmentViewInfo, error) {
+	v, err := resolveView(resolve, a.View)
+	if err != nil {
+		return v, err
+	}
+	if !v.Format.IsDepthStencil() {
+		return v, fmt.Errorf("%w: %s is not a depth/stencil format", ErrAttachmentFormat, v.Format)
+	}
+
+	if err := checkAspectOps("depth", v.Format.HasDepth(), a.DepthReadOnly, a.DepthLoadOp, a.DepthStoreOp); err != nil {
+		return v, err
+	}
+	if err := checkAspectOps("stencil", v.Format.HasStencil(), a.StencilReadOnly, a.StencilLoadOp, a.StencilStoreOp); err != nil {
+		return v, err
+	}
+	if a.DepthLoadOp == LoadOpClear && !(a.DepthClearValue >= 0 && a.DepthClearValue <= 1) {
+		return v, fmt.Errorf("%w: depth %v", ErrClearValue, a.DepthClearValue)
+	}
+	return v, nil
+}
+
+// checkAspectOps checks the load and store operations of one aspect of a
+// depth/stencil attachment: they must be defined if the format has the
+// aspect and it is writable, and undefined otherwise.
+func checkAspectOps(aspect string, present, readOnly bool, load LoadOp, store StoreOp) error {
+	if !present || readOnly {
+		why := "read-only"
+		if !present {
+			why = "absent"
+		}
+		if load != LoadOpUndefined {
+			return fmt.Errorf("%w: %s %s with %s aspect", ErrLoadOp, aspect, load, why)
+		}
+		if store != StoreOpUndefined {
+			return fmt.Errorf("%w: %s %s with %s aspect", ErrStoreOp, aspect, store, why)
+		}
+		return nil
+	}
+	if load != LoadOpLoad && load != LoadOpClear {
+		return fmt.Errorf("%w: %s %s", ErrLoadOp, aspect, load)
+	}
+	if store != StoreOpStore && store != StoreOpDiscard {
+		return fmt.Errorf("%w: %s %s", ErrStoreOp, aspect, store)
+	}
+	return nil
+}
+
+// checkColorClearValue checks that an integer format can represent c.
+// Other formats accept any value; it is converted with clamping.
+func checkColorClearValue(f TextureFormat, c Color) error {
+	lo, hi, n, ok := f.integerRange()
+	if !ok {
+		return nil
+	}
+	v4 := [4]float64{c.R, c.G, c.B, c.A}
+	for i, v := range v4[:n] {
+		if !(v >= lo[i] && v <= hi[i]) {
+			return fmt.Errorf("%w: component %d = %v not in [%v, %v] for %s", ErrClearValue, i, v, lo[i], hi[i], f)
+		}
+	}
+	return nil
+}
+
+// integerRange returns the per-channel value range and channel count of an
+// integer color format. ok is false for other formats.
+func (f TextureFormat) integerRange() (lo, hi [4]float64, channels int, ok bool) {
+	uintRange := func(bits int) (float64, float64) { return 0, math.Ldexp(1, bits) - 1 }
+	sintRange := func(bits int) (float64, float64) { return -math.Ldexp(1, bits-1), math.Ldexp(1, bits-1) - 1 }
+
+	var l, h float64
+	switch f {
+	case TextureFormatR8Uint, TextureFormatRG8Uint, TextureFormatRGBA8Uint:
+		l, h = uintRange(8)
+	case TextureFormatR8Sint, TextureFormatRG8Sint, TextureFormatRGBA8Sint:
+		l, h = sintRange(8)
+	case TextureFormatR16Uint, TextureFormatRG16Uint, TextureFormatRGBA16Uint:
+		l, h = uintRange(16)
+	case TextureFormatR16Sint, TextureFormatRG16Sint, TextureFormatRGBA16Sint:
+		l, h = sintRange(16)
+	case TextureFormatR32Uint, TextureFormatRG32Uint, TextureFormatRGBA32Uint:
+		l, h = uintRange(32)
+	case TextureFormatR32Sint, TextureFormatRG32Sint, TextureFormatRGBA32Sint:
+		l, h = sintRange(32)
+	case TextureFormatRGB10A2Uint:
+		return [4]float64{}, [4]float64{1023, 1023, 1023, 3}, 4, true
+	default:
+		return lo, hi, 0, false
+	}
+
+	switch f {
+	case TextureFormatR8Uint, TextureFormatR8Sint, TextureFormatR16Uint, TextureFormatR16Sint,
+		TextureFormatR32Uint, TextureFormatR32Sint:
+		channels = 1
+	case TextureFormatRG8Uint, TextureFormatRG8Sint, TextureFormatRG16Uint, TextureFormatRG16Sint,
+		TextureFormatRG32Uint, TextureFormatRG32Sint:
+		channels = 2
+	default:
+		channels = 4
+	}
+	for i := range channels {
+		lo[i], hi[i] = l, h
+	}
+	return lo, hi, channels, true
+}
@@ -0,0 +1,208 @@
+package gputypes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testViews resolves the view handles used by the render pass tests.
+func testViews(view uintptr) (AttachmentViewInfo, bool) {
+	rt := TextureUsageRenderAttachment
+	views := map[uintptr]AttachmentViewInfo{
+		1:  {Format: TextureFormatRGBA8Unorm, Width: 64, Height: 64, Usage: rt},
+		2:  {Format: TextureFormatRGBA8Unorm, Width: 64, Height: 64, SampleCount: 4, Usage: rt},
+		3:  {Format: TextureFormatDepth24PlusStencil8, Width: 64, Height: 64, Usage: rt},
+		4:  {Format: TextureFormatDepth32Float, Width: 64, Height: 64, SampleCount: 4, Usage: rt},
+		5:  {Format: TextureFormatRGBA8Unorm, Width: 32, Height: 64, Usage: rt},
+		6:  {Format: TextureFormatRGBA8Unorm, Width: 64, Height: 64, Usage: TextureUsageTextureBinding},
+		7:  {Format: TextureFormatR8Uint, Width: 64, Height: 64, Usage: rt},
+		8:  {Format: TextureFormatBGRA8Unorm, Width: 64, Height: 64, Usage: rt},
+		9:  {Format: TextureFormatRGBA8Uint, Width: 64, Height: 64, SampleCount: 4, Usage: rt},
+		10: {Format: TextureFormatRGBA8Unorm, Dimension: TextureViewDimension3D, Width: 64, Height: 64, Depth: 4, Usage: rt},
+		11: {Format: TextureFormatRGBA16Float, Width: 64, Height: 64, Usage: rt},
+		12: {Format: TextureFormatRGBA32Float, Width: 64, Height: 64, Usage: rt},
+	}
+	v, ok := views[view]
+	return v, ok
+}
+
+func TestRenderPassDescriptor_Validate(t *testing.T) {
+	color := func(view uintptr) RenderPassColorAttachment {
+		return RenderPassColorAttachment{View: view, LoadOp: LoadOpClear, StoreOp: StoreOpStore}
+	}
+	depth := func(view uintptr) *RenderPassDepthStencilAttachment {
+		return &RenderPassDepthStencilAttachment{
+			View:        view,
+			DepthLoadOp: LoadOpClear, DepthStoreOp: StoreOpStore, DepthClearValue: 1,
+		}
+	}
+	depthStencil := depth(3)
+	depthStencil.StencilLoadOp, depthStencil.StencilStoreOp = LoadOpLoad, StoreOpStore
+
+	tests := []struct {
+		name  string
+		desc  RenderPassDescriptor
+		color int
+		ds    bool
+		want  error
+	}{
+		{"color only", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1)}}, -1, false, nil},
+		{"depth only", RenderPassDescriptor{DepthStencilAttachment: depth(4)}, -1, false, nil},
+		{"depth and stencil", RenderPassDescriptor{
+			ColorAttachments:       []RenderPassColorAttachment{{}, color(8)},
+			DepthStencilAttachment: depthStencil,
+		}, -1, false, nil},
+		{"read-only depth", RenderPassDescriptor{DepthStencilAttachment: &RenderPassDepthStencilAttachment{
+			View: 3, DepthReadOnly: true, StencilReadOnly: true,
+		}}, -1, false, nil},
+		{"msaa resolve", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 2, ResolveTarget: 1, LoadOp: LoadOpClear, StoreOp: StoreOpDiscard},
+		}, DepthStencilAttachment: depth(4)}, -1, false, nil},
+
+		{"3d slices", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 10, DepthSlice: 3, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+			color(10),
+		}}, -1, false, nil},
+		{"timestamps", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1)},
+			TimestampWrites:   &PassTimestampWrites{QuerySet: 1, BeginningOfPassWriteIndex: QuerySetIndexUndefined},
+			OcclusionQuerySet: 2}, -1, false, nil},
+
+		{"empty", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{{}}}, -1, false, ErrNoAttachments},
+		{"too many", RenderPassDescriptor{ColorAttachments: make([]RenderPassColorAttachment, 9)}, -1, false, ErrTooManyColorAttachments},
+		{"bytes per sample", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			color(1), color(11), color(12), color(8),
+		}}, -1, false, ErrColorAttachmentBytesPerSample},
+		{"unknown view", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(99)}}, 0, false, ErrAttachmentView},
+		{"usage", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1), color(6)}}, 1, false, ErrAttachmentUsage},
+		{"depth as color", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(3)}}, 0, false, ErrAttachmentFormat},
+		{"color as depth", RenderPassDescriptor{DepthStencilAttachment: depth(1)}, -1, true, ErrAttachmentFormat},
+		{"size", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1), color(5)}}, 1, false, ErrAttachmentSize},
+		{"sample count", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1)},
+			DepthStencilAttachment: depth(4)}, -1, true, ErrAttachmentSampleCount},
+		{"undefined load", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 1, StoreOp: StoreOpStore},
+		}}, 0, false, ErrLoadOp},
+		{"undefined store", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 1, LoadOp: LoadOpLoad},
+		}}, 0, false, ErrStoreOp},
+		{"resolve single-sampled", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 1, ResolveTarget: 8, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrResolveTarget},
+		{"resolve into msaa", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 2, ResolveTarget: 2, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrResolveTarget},
+		{"resolve format", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 2, ResolveTarget: 8, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrResolveTarget},
+		{"resolve integer", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 9, ResolveTarget: 7, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrResolveTarget},
+		{"integer clear", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 7, LoadOp: LoadOpClear, StoreOp: StoreOpStore, ClearValue: Color{R: 256}},
+		}}, 0, false, ErrClearValue},
+		{"depth clear", RenderPassDescriptor{DepthStencilAttachment: &RenderPassDepthStencilAttachment{
+			View: 4, DepthLoadOp: LoadOpClear, DepthStoreOp: StoreOpStore, DepthClearValue: 1.5,
+		}}, -1, true, ErrClearValue},
+		{"stencil ops on depth-only", RenderPassDescriptor{DepthStencilAttachment: &RenderPassDepthStencilAttachment{
+			View: 4, DepthLoadOp: LoadOpLoad, DepthStoreOp: StoreOpStore, StencilLoadOp: LoadOpLoad,
+		}}, -1, true, ErrLoadOp},
+		{"missing stencil ops", RenderPassDescriptor{DepthStencilAttachment: depth(3)}, -1, true, ErrLoadOp},
+		{"slice out of range", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 10, DepthSlice: 4, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrDepthSlice},
+		{"slice on 2d", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{
+			{View: 1, DepthSlice: 1, LoadOp: LoadOpLoad, StoreOp: StoreOpStore},
+		}}, 0, false, ErrDepthSlice},
+		{"slice reused", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(10), color(10)}},
+			1, false, ErrDepthSlice},
+		{"timestamps without indices", RenderPassDescriptor{ColorAttachments: []RenderPassColorAttachment{color(1)},
+			TimestampWrites: &PassTimestampWrites{QuerySet: 1, BeginningOfPassWriteIndex: QuerySetIndexUndefined,
+				EndOfPassWriteIndex: QuerySetIndexUndefined}}, -1, false, ErrTimestampWrites},
+		{"read-only with store", RenderPassDescriptor{DepthStencilAttachment: &RenderPassDepthStencilAttachment{
+			View: 3, DepthReadOnly: true, DepthStoreOp: StoreOpStore, StencilReadOnly: true,
+		}}, -1, true, ErrStoreOp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.desc.Validate(DefaultLimits(), testViews)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.want)
+			}
+			var pe *RenderPassError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Validate() error %T is not *RenderPassError", err)
+			}
+			if pe.Color != tt.color || pe.DepthStencil != tt.ds {
+				t.Errorf("RenderPassError = {Color: %d, DepthStencil: %v}, want {%d, %v}", pe.Color, pe.DepthStencil, tt.color, tt.ds)
+			}
+			if !strings.HasPrefix(err.Error(), "gputypes: render pass") {
+				t.Errorf("Error() = %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestCheckColorClearValue(t *testing.T) {
+	tests := []struct {
+		format TextureFormat
+		c      Color
+		ok     bool
+	}{
+		{TextureFormatRGBA8Unorm, Color{R: 2, G: -1}, true},
+		{TextureFormatR8Uint, Color{R: 255, G: 1000}, true},
+		{TextureFormatR8Sint, Color{R: -129}, false},
+		{TextureFormatRG16Sint, Color{R: -32768, G: 32767}, true},
+		{TextureFormatRGBA32Uint, Color{A: 4294967295}, true},
+		{TextureFormatRGBA32Uint, Color{A: -1}, false},
+		{TextureFormatRGB10A2Uint, Color{R: 1023, A: 4}, false},
+	}
+	for _, tt := range tests {
+		if err := checkColorClearValue(tt.format, tt.c); (err == nil) != tt.ok {
+			t.Errorf("checkColorClearValue(%s, %+v) = %v, want ok = %v", tt.format, tt.c, err, tt.ok)
+		}
+	}
+}
+
+func TestPassTimestampWrites_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		writes     PassTimestampWrites
+		begin, end uint32
+		ok         bool
+	}{
+		{"both", PassTimestampWrites{QuerySet: 1}, 0, 1, true},
+		{"end only", PassTimestampWrites{QuerySet: 1}, QuerySetIndexUndefined, 7, true},
+		{"no query set", PassTimestampWrites{}, 0, 1, false},
+		{"same index", PassTimestampWrites{QuerySet: 1}, 2, 2, false},
+		{"out of range", PassTimestampWrites{QuerySet: 1}, 0, 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.writes
+			w.BeginningOfPassWriteIndex, w.EndOfPassWriteIndex = tt.begin, tt.end
+			err := w.Validate(8)
+			if (err == nil) != tt.ok {
+				t.Fatalf("Validate(8) = %v, want ok = %v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrTimestampWrites) {
+				t.Errorf("Validate(8) = %v, want ErrTimestampWrites", err)
+			}
+		})
+	}
+}
+
+func TestRenderPassDescriptor_DrawCountLimit(t *testing.T) {
+	if got := (&RenderPassDescriptor{}).DrawCountLimit(); got != DefaultMaxDrawCount {
+		t.Errorf("zero MaxDrawCount limit = %d, want %d", got, DefaultMaxDrawCount)
+	}
+	if got := (&RenderPassDescriptor{MaxDrawCount: 10}).DrawCountLimit(); got != 10 {
+		t.Errorf("DrawCountLimit() = %d, want 10", got)
+	}
+}
@@ -0,0 +1,227 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Render pipeline validation errors.
+//
+// RenderPipelineDescriptor.Validate wraps these in a *RenderPipelineError
+// that records which state the problem was found in; test for them with
+// errors.Is.
+var (
+	// ErrMissingFeature means the state requires a feature that is not enabled.
+	ErrMissingFeature = errors.New("required feature not enabled")
+	// ErrStripIndexFormat means StripIndexFormat is set for a list topology, or is not a valid index format.
+	ErrStripIndexFormat = errors.New("invalid strip index format")
+	// ErrSampleCount means the multisample count is not 1 or 4.
+	ErrSampleCount = errors.New("invalid sample count")
+	// ErrAlphaToCoverage means alpha-to-coverage is enabled without multisampling.
+	ErrAlphaToCoverage = errors.New("alpha-to-coverage requires a sample count greater than 1")
+	// ErrDepthStencilFormat means the depth/stencil format is not a depth or stencil format.
+	ErrDepthStencilFormat = errors.New("invalid depth/stencil format")
+	// ErrDepthStencilAspect means a depth or stencil field is used but the format lacks that aspect.
+	ErrDepthStencilAspect = errors.New("depth/stencil state uses an aspect the format does not have")
+	// ErrDepthCompare means DepthCompare is undefined for a format with a
+	// depth aspect while depth writes are enabled or a stencil face's
+	// DepthFailOp is not Keep.
+	ErrDepthCompare = errors.New("depth compare function is required")
+	// ErrDepthBias means depth bias is set for a point or line topology.
+	ErrDepthBias = errors.New("depth bias requires a triangle topology")
+	// ErrTooManyColorTargets means more color targets than Limits.MaxColorAttachments.
+	ErrTooManyColorTargets = errors.New("too many color targets")
+	// ErrColorAttachmentBytesPerSample means the color targets exceed Limits.MaxColorAttachmentBytesPerSample.
+	ErrColorAttachmentBytesPerSample = errors.New("color attachment bytes per sample exceed limit")
+	// ErrColorTargetFormat means the target format is not color renderable.
+	ErrColorTargetFormat = errors.New("format is not color renderable")
+	// ErrBlendNotSupported means blending is set for a format that is not blendable.
+	ErrBlendNotSupported = errors.New("format is not blendable")
+	// ErrColorWriteMask means the write mask has bits outside ColorWriteMaskAll.
+	ErrColorWriteMask = errors.New("color write mask has unknown bits")
+)
+
+// RenderPipelineError reports an invalid render pipeline state and where it
+// was found.
+type RenderPipelineError struct {
+	// State names the descriptor field holding the problem: "primitive",
+	// "multisample", "depthStencil" or "fragment".
+	State string
+	// Target is the index into FragmentState.Targets, or -1 if the error
+	// does not concern a single color target.
+	Target int
+	// Err describes the problem and wraps one of the Err* sentinels.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RenderPipelineError) Error() string {
+	if e.Target < 0 {
+		return fmt.Sprintf("gputypes: render pipeline %s: %v", e.State, e.Err)
+	}
+	return fmt.Sprintf("gputypes: render pipeline %s target %d: %v", e.State, e.Target, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RenderPipelineError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the pipeline state against the WebGPU rules, the given
+// limits and the enabled features, returning the first violation.
+//
+// Vertex state errors are returned as *VertexLayoutError (see
+// VertexState.Validate); all others as *RenderPipelineError. It checks that:
+//   - StripIndexFormat is only set for strip topologies
+//   - UnclippedDepth has FeatureDepthClipControl
+//   - the sample count is 1 or 4, and alpha-to-coverage is only enabled
+//     when it is greater than 1
+//   - the depth/stencil format is a depth or stencil format with the
+//     aspects the depth and stencil fields use, DepthCompare is set when
+//     depth writes are enabled or a DepthFailOp is not Keep, and depth bias
+//     is only set for triangle topologies
+//   - there are at most MaxColorAttachments color targets, their formats
+//     are color renderable, and together they fit
+//     MaxColorAttachmentBytesPerSample
+//   - blending is only set on blendable formats, and WriteMask has no
+//     unknown bits
+//
+// Zero-value Multisample fields are read as the spec defaults (see
+// MultisampleState.Normalized).
+func (d *RenderPipelineDescriptor) Validate(limits Limits, features Features) error {
+	if err := d.Vertex.Validate(limits); err != nil {
+		return err
+	}
+	if err := d.validatePrimitive(features); err != nil {
+		return &RenderPipelineError{State: "primitive", Target: -1, Err: err}
+	}
+	if err := d.validateMultisample(); err != nil {
+		return &RenderPipelineError{State: "multisample", Target: -1, Err: err}
+	}
+	if d.DepthStencil != nil {
+		if err := d.validateDepthStencil(features); err != nil {
+			return &RenderPipelineError{State: "depthStencil", Target: -1, Err: err}
+		}
+	}
+	if d.Fragment != nil {
+		if err := d.Fragment.validateTargets(limits, features); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePrimitive checks the primitive state.
+func (d *RenderPipelineDescriptor) validatePrimitive(features Features) error {
+	p := d.Primitive
+	if p.StripIndexFormat != nil {
+		if !p.Topology.IsStrip() {
+			return fmt.Errorf("%w: set for %s", ErrStripIndexFormat, p.Topology)
+		}
+		if f := *p.StripIndexFormat; f != IndexFormatUint16 && f != IndexFormatUint32 {
+			return fmt.Errorf("%w: %s", ErrStripIndexFormat, f)
+		}
+	}
+	if p.UnclippedDepth && !features.Contains(FeatureDepthClipControl) {
+		return fmt.Errorf("%w: UnclippedDepth needs %s", ErrMissingFeature, FeatureDepthClipControl)
+	}
+	return nil
+}
+
+// validateMultisample checks the multisample state.
+func (d *RenderPipelineDescriptor) validateMultisample() error {
+	m := d.Multisample.Normalized()
+	if m.Count != 1 && m.Count != 4 {
+		return fmt.Errorf("%w: %d", ErrSampleCount, m.Count)
+	}
+	if m.AlphaToCoverageEnabled && m.Count == 1 {
+		return ErrAlphaToCoverage
+	}
+	return nil
+}
+
+// validateDepthStencil checks the depth/stencil state against the topology.
+func (d *RenderPipelineDescriptor) validateDepthStencil(features Features) error {
+	ds := d.DepthStencil
+	if !ds.Format.IsDepthStencil() {
+		return fmt.Errorf("%w: %s", ErrDepthStencilFormat, ds.Format)
+	}
+	if ds.Format == TextureFormatDepth32FloatStencil8 && !features.Contains(FeatureDepth32FloatStencil8) {
+		return fmt.Errorf("%w: %s needs %s", ErrMissingFeature, ds.Format, FeatureDepth32FloatStencil8)
+	}
+
+	if ds.Format.HasDepth() {
+		keep := func(op StencilOperation) bool {
+			return op == StencilOperationUndefined || op == StencilOperationKeep
+		}
+		if ds.DepthCompare == CompareFunctionUndefined &&
+			(ds.DepthWriteEnabled || !keep(ds.StencilFront.DepthFailOp) || !keep(ds.StencilBack.DepthFailOp)) {
+			return fmt.Errorf("%w: depth writes or a DepthFailOp other than Keep", ErrDepthCompare)
+		}
+	} else {
+		if ds.DepthWriteEnabled {
+			return fmt.Errorf("%w: DepthWriteEnabled with %s", ErrDepthStencilAspect, ds.Format)
+		}
+		if ds.DepthCompare != CompareFunctionUndefined && ds.DepthCompare != CompareFunctionAlways {
+			return fmt.Errorf("%w: DepthCompare %s with %s", ErrDepthStencilAspect, ds.DepthCompare, ds.Format)
+		}
+	}
+	if !ds.Format.HasStencil() && (ds.StencilFront.usesStencil() || ds.StencilBack.usesStencil()) {
+		return fmt.Errorf("%w: stencil test with %s", ErrDepthStencilAspect, ds.Format)
+	}
+
+	switch d.Primitive.Topology {
+	case PrimitiveTopologyPointList, PrimitiveTopologyLineList, PrimitiveTopologyLineStrip:
+		if ds.DepthBias != 0 || ds.DepthBiasSlopeScale != 0 || ds.DepthBiasClamp != 0 {
+			return fmt.Errorf("%w: set for %s", ErrDepthBias, d.Primitive.Topology)
+		}
+	}
+	return nil
+}
+
+// usesStencil returns true if the face state does anything other than
+// always pass and keep the stencil value.
+func (s StencilFaceState) usesStencil() bool {
+	keep := func(op StencilOperation) bool {
+		return op == StencilOperationUndefined || op == StencilOperationKeep
+	}
+	return (s.Compare != CompareFunctionUndefined && s.Compare != CompareFunctionAlways) ||
+		!keep(s.FailOp) || !keep(s.DepthFailOp) || !keep(s.PassOp)
+}
+
+// validateTargets checks the color targets. Targets with an undefined
+// format are unused slots and are skipped.
+func (s *FragmentState) validateTargets(limits Limits, features Features) error {
+	fragErr := func(target int, err error) error {
+		return &RenderPipelineError{State: "fragment", Target: target, Err: err}
+	}
+
+	if len(s.Targets) > int(limits.MaxColorAttachments) {
+		return fragErr(-1, fmt.Errorf("%w: %d > %d", ErrTooManyColorTargets, len(s.Targets), limits.MaxColorAttachments))
+	}
+
+	formats := make([]TextureFormat, 0, len(s.Targets))
+	for i, t := range s.Targets {
+		if t.Format == TextureFormatUndefined {
+			continue
+		}
+		if !t.Format.IsColorRenderable() {
+			return fragErr(i, fmt.Errorf("%w: %s", ErrColorTargetFormat, t.Format))
+		}
+		if t.Format == TextureFormatRG11B10Ufloat && !features.Contains(FeatureRG11B10UfloatRenderable) {
+			return fragErr(i, fmt.Errorf("%w: %s needs %s", ErrMissingFeature, t.Format, FeatureRG11B10UfloatRenderable))
+		}
+		if t.Blend != nil && !t.Format.IsBlendable() {
+			return fragErr(i, fmt.Errorf("%w: %s", ErrBlendNotSupported, t.Format))
+		}
+		if t.WriteMask&^ColorWriteMaskAll != 0 {
+			return fragErr(i, fmt.Errorf("%w: %#x", ErrColorWriteMask, uint32(t.WriteMask)))
+		}
+		formats = append(formats, t.Format)
+	}
+
+	if n := ColorAttachmentBytesPerSample(formats...); n > limits.MaxColorAttachmentBytesPerSample {
+		return fragErr(-1, fmt.Errorf("%w: %d > %d", ErrColorAttachmentBytesPerSample, n, limits.MaxColorAttachmentBytesPerSample))
+	}
+	return nil
+}
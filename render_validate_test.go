@@ -0,0 +1,169 @@
+package gputypes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestColorAttachmentBytesPerSample(t *testing.T) {
+	tests := []struct {
+		formats []TextureFormat
+		want    uint32
+	}{
+		{nil, 0},
+		{[]TextureFormat{TextureFormatRGBA8Unorm}, 8},
+		{[]TextureFormat{TextureFormatR8Unorm, TextureFormatR32Float}, 8},
+		{[]TextureFormat{TextureFormatR8Uint, TextureFormatRG16Float, TextureFormatR8Unorm}, 7},
+		{[]TextureFormat{TextureFormatRGBA32Float, TextureFormatUndefined, TextureFormatRGBA16Float}, 24},
+		{[]TextureFormat{TextureFormatDepth32Float}, 0},
+	}
+	for _, tt := range tests {
+		if got := ColorAttachmentBytesPerSample(tt.formats...); got != tt.want {
+			t.Errorf("ColorAttachmentBytesPerSample(%v) = %d, want %d", tt.formats, got, tt.want)
+		}
+	}
+}
+
+func TestTextureFormat_RenderTarget(t *testing.T) {
+	if !TextureFormatBGRA8Unorm.IsColorRenderable() || !TextureFormatBGRA8Unorm.IsBlendable() {
+		t.Error("BGRA8Unorm should be renderable and blendable")
+	}
+	if !TextureFormatRGBA32Float.IsColorRenderable() || TextureFormatRGBA32Float.IsBlendable() {
+		t.Error("RGBA32Float should be renderable and not blendable")
+	}
+	for _, f := range []TextureFormat{TextureFormatRGBA8Snorm, TextureFormatDepth24Plus, TextureFormatBC1RGBAUnorm, TextureFormatRGB9E5Ufloat} {
+		if f.IsColorRenderable() || f.RenderTargetPixelByteCost() != 0 || f.RenderTargetComponentAlignment() != 0 {
+			t.Errorf("%s reported as color renderable", f)
+		}
+	}
+}
+
+func validRenderPipeline() RenderPipelineDescriptor {
+	ds := DefaultDepthStencilState(TextureFormatDepth24PlusStencil8)
+	blend := BlendStateAlpha()
+	return RenderPipelineDescriptor{
+		Vertex: VertexState{Buffers: []VertexBufferLayout{{
+			ArrayStride: 12,
+			Attributes:  []VertexAttribute{{Format: VertexFormatFloat32x3}},
+		}}},
+		DepthStencil: &ds,
+		Fragment: &FragmentState{Targets: []ColorTargetState{
+			{Format: TextureFormatBGRA8Unorm, Blend: &blend, WriteMask: ColorWriteMaskAll},
+		}},
+	}
+}
+
+func TestRenderPipelineDescriptor_Validate(t *testing.T) {
+	u16 := IndexFormatUint16
+	tests := []struct {
+		name     string
+		modify   func(d *RenderPipelineDescriptor)
+		features Features
+		want     error
+		target   int
+	}{
+		{"valid", func(*RenderPipelineDescriptor) {}, 0, nil, -1},
+		{"zero value", func(d *RenderPipelineDescriptor) { *d = RenderPipelineDescriptor{} }, 0, nil, -1},
+		{"strip index format on strip", func(d *RenderPipelineDescriptor) {
+			d.Primitive = PrimitiveState{Topology: PrimitiveTopologyTriangleStrip, StripIndexFormat: &u16}
+		}, 0, nil, -1},
+		{"strip index format on list", func(d *RenderPipelineDescriptor) {
+			d.Primitive.StripIndexFormat = &u16
+		}, 0, ErrStripIndexFormat, -1},
+		{"unclipped depth without feature", func(d *RenderPipelineDescriptor) {
+			d.Primitive.UnclippedDepth = true
+		}, 0, ErrMissingFeature, -1},
+		{"unclipped depth with feature", func(d *RenderPipelineDescriptor) {
+			d.Primitive.UnclippedDepth = true
+		}, Features(FeatureDepthClipControl), nil, -1},
+		{"sample count", func(d *RenderPipelineDescriptor) {
+			d.Multisample.Count = 2
+		}, 0, ErrSampleCount, -1},
+		{"alpha to coverage single sample", func(d *RenderPipelineDescriptor) {
+			d.Multisample.AlphaToCoverageEnabled = true
+		}, 0, ErrAlphaToCoverage, -1},
+		{"alpha to coverage multisampled", func(d *RenderPipelineDescriptor) {
+			d.Multisample = MultisampleState{Count: 4, AlphaToCoverageEnabled: true}
+		}, 0, nil, -1},
+		{"color depth format", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.Format = TextureFormatRGBA8Unorm
+		}, 0, ErrDepthStencilFormat, -1},
+		{"depth32 stencil8 without feature", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.Format = TextureFormatDepth32FloatStencil8
+		}, 0, ErrMissingFeature, -1},
+		{"missing depth compare", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.DepthCompare = CompareFunctionUndefined
+		}, 0, ErrDepthCompare, -1},
+		{"depth fail op without depth compare", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.DepthWriteEnabled = false
+			d.DepthStencil.DepthCompare = CompareFunctionUndefined
+			d.DepthStencil.StencilBack.DepthFailOp = StencilOperationIncrementClamp
+		}, 0, ErrDepthCompare, -1},
+		{"stencil only without depth compare", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.DepthWriteEnabled = false
+			d.DepthStencil.DepthCompare = CompareFunctionUndefined
+			d.DepthStencil.StencilFront = StencilFaceState{Compare: CompareFunctionEqual, PassOp: StencilOperationReplace}
+		}, 0, nil, -1},
+		{"depth write on stencil8", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.Format = TextureFormatStencil8
+		}, 0, ErrDepthStencilAspect, -1},
+		{"stencil test on depth only", func(d *RenderPipelineDescriptor) {
+			d.DepthStencil.Format = TextureFormatDepth32Float
+			d.DepthStencil.StencilBack.PassOp = StencilOperationReplace
+		}, 0, ErrDepthStencilAspect, -1},
+		{"depth bias on lines", func(d *RenderPipelineDescriptor) {
+			d.Primitive.Topology = PrimitiveTopologyLineList
+			d.DepthStencil.DepthBias = 2
+		}, 0, ErrDepthBias, -1},
+		{"too many targets", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets = make([]ColorTargetState, 9)
+		}, 0, ErrTooManyColorTargets, -1},
+		{"bytes per sample", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets = []ColorTargetState{
+				{Format: TextureFormatRGBA32Float}, {Format: TextureFormatRGBA32Float}, {Format: TextureFormatR8Unorm},
+			}
+		}, 0, ErrColorAttachmentBytesPerSample, -1},
+		{"sparse targets", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets = []ColorTargetState{{}, {Format: TextureFormatRGBA32Float}, {}, {Format: TextureFormatRGBA32Float}}
+		}, 0, nil, -1},
+		{"non-renderable target", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets = append(d.Fragment.Targets, ColorTargetState{Format: TextureFormatRGBA8Snorm})
+		}, 0, ErrColorTargetFormat, 1},
+		{"rg11b10 without feature", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets[0].Format = TextureFormatRG11B10Ufloat
+		}, 0, ErrMissingFeature, 0},
+		{"blend on integer format", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets[0].Format = TextureFormatRGBA8Uint
+		}, 0, ErrBlendNotSupported, 0},
+		{"write mask unknown bits", func(d *RenderPipelineDescriptor) {
+			d.Fragment.Targets[0].WriteMask = 0x10
+		}, 0, ErrColorWriteMask, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := validRenderPipeline()
+			tt.modify(&d)
+			err := d.Validate(DefaultLimits(), tt.features)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("Validate() = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil {
+				return
+			}
+			var pe *RenderPipelineError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Validate() error %T is not *RenderPipelineError", err)
+			}
+			if pe.Target != tt.target {
+				t.Errorf("Target = %d, want %d", pe.Target, tt.target)
+			}
+		})
+	}
+
+	d := validRenderPipeline()
+	d.Vertex.Buffers[0].ArrayStride = 3
+	var ve *VertexLayoutError
+	if err := d.Validate(DefaultLimits(), 0); !errors.As(err, &ve) {
+		t.Errorf("Validate() with bad vertex layout = %v, want *VertexLayoutError", err)
+	}
+}
@@ -678,6 +678,184 @@ func (f TextureFormat) BlockCopySize() uint32 {
 	}
 }
 
+// renderTargetInfo holds the color attachment properties of a format.
+type renderTargetInfo struct {
+	byteCost  uint32
+	alignment uint32
+	blendable bool
+}
+
+// renderTarget returns the color attachment properties of f, following the
+// WebGPU "Plain color formats" table. ok is false for formats that are not
+// color renderable.
+func (f TextureFormat) renderTarget() (info renderTargetInfo, ok bool) {
+	switch f {
+	case TextureFormatR8Unorm:
+		return renderTargetInfo{1, 1, true}, true
+	case TextureFormatR8Uint, TextureFormatR8Sint:
+		return renderTargetInfo{1, 1, false}, true
+	case TextureFormatR16Unorm, TextureFormatR16Snorm, TextureFormatR16Float:
+		return renderTargetInfo{2, 2, true}, true
+	case TextureFormatR16Uint, TextureFormatR16Sint:
+		return renderTargetInfo{2, 2, false}, true
+	case TextureFormatRG8Unorm:
+		return renderTargetInfo{2, 1, true}, true
+	case TextureFormatRG8Uint, TextureFormatRG8Sint:
+		return renderTargetInfo{2, 1, false}, true
+	case TextureFormatR32Float, TextureFormatR32Uint, TextureFormatR32Sint:
+		return renderTargetInfo{4, 4, false}, true
+	case TextureFormatRG16Unorm, TextureFormatRG16Snorm, TextureFormatRG16Float:
+		return renderTargetInfo{4, 2, true}, true
+	case TextureFormatRG16Uint, TextureFormatRG16Sint:
+		return renderTargetInfo{4, 2, false}, true
+	case TextureFormatRGBA8Unorm, TextureFormatRGBA8UnormSrgb,
+		TextureFormatBGRA8Unorm, TextureFormatBGRA8UnormSrgb:
+		return renderTargetInfo{8, 1, true}, true
+	case TextureFormatRGBA8Uint, TextureFormatRGBA8Sint:
+		return renderTargetInfo{4, 1, false}, true
+	case TextureFormatRGB10A2Unorm, TextureFormatRG11B10Ufloat:
+		return renderTargetInfo{8, 4, true}, true
+	case TextureFormatRGB10A2Uint:
+		return renderTargetInfo{8, 4, false}, true
+	case TextureFormatRG32Float, TextureFormatRG32Uint, TextureFormatRG32Sint:
+		return renderTargetInfo{8, 4, false}, true
+	case TextureFormatRGBA16Unorm, TextureFormatRGBA16Snorm, TextureFormatRGBA16Float:
+		return renderTargetInfo{8, 2, true}, true
+	case TextureFormatRGBA16Uint, TextureFormatRGBA16Sint:
+		return renderTargetInfo{8, 2, false}, true
+	case TextureFormatRGBA32Float, TextureFormatRGBA32Uint, TextureFormatRGBA32Sint:
+		return renderTargetInfo{16, 4, false}, true
+	default:
+		return renderTargetInfo{}, false
+	}
+}
+
+// IsColorRenderable returns true if f can be used as a color attachment.
+//
+// RG11B10Ufloat additionally requires FeatureRG11B10UfloatRenderable, and
+// the 16-bit normalized formats require adapter support.
+func (f TextureFormat) IsColorRenderable() bool {
+	_, ok := f.renderTarget()
+	return ok
+}
+
+// IsBlendable returns true if f is a color renderable format that supports
+// blending. Integer and 32-bit float formats are not blendable.
+func (f TextureFormat) IsBlendable() bool {
+	info, _ := f.renderTarget()
+	return info.blendable
+}
+
+// RenderTargetPixelByteCost returns the bytes one sample of f occupies in
+// tile memory, as counted against Limits.MaxColorAttachmentBytesPerSample.
+//
+// Returns 0 for formats that are not color renderable.
+func (f TextureFormat) RenderTargetPixelByteCost() uint32 {
+	info, _ := f.renderTarget()
+	return info.byteCost
+}
+
+// RenderTargetComponentAlignment returns the alignment of f when color
+// attachments are packed into tile memory.
+//
+// Returns 0 for formats that are not color renderable.
+func (f TextureFormat) RenderTargetComponentAlignment() uint32 {
+	info, _ := f.renderTarget()
+	return info.alignment
+}
+
+// ColorAttachmentBytesPerSample returns the tile memory used by one sample
+// of the given color attachment formats, packed in order with each format
+// aligned to its RenderTargetComponentAlignment. Undefined formats mark
+// unused attachment slots and are skipped.
+func ColorAttachmentBytesPerSample(formats ...TextureFormat) uint32 {
+	var total uint32
+	for _, f := range formats {
+		info, ok := f.renderTarget()
+		if !ok {
+			continue
+		}
+		total = (total+info.alignment-1)/info.alignment*info.alignment + info.byteCost
+	}
+	return total
+}
+
+// SupportsStorageAccess reports whether f can be bound as a storage texture
+// with the given access. StorageTextureAccessUndefined is read as WriteOnly.
+//
+// ReadWrite access is limited to the single-channel 32-bit formats.
+// BGRA8Unorm supports WriteOnly access with FeatureBGRA8UnormStorage.
+func (f TextureFormat) SupportsStorageAccess(access StorageTextureAccess, features Features) bool {
+	switch access {
+	case StorageTextureAccessUndefined, StorageTextureAccessWriteOnly, StorageTextureAccessReadOnly:
+	case StorageTextureAccessReadWrite:
+		switch f {
+		case TextureFormatR32Uint, TextureFormatR32Sint, TextureFormatR32Float:
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+
+	switch f {
+	case TextureFormatRGBA8Unorm, TextureFormatRGBA8Snorm, TextureFormatRGBA8Uint, TextureFormatRGBA8Sint,
+		TextureFormatRGBA16Uint, TextureFormatRGBA16Sint, TextureFormatRGBA16Float,
+		TextureFormatR32Uint, TextureFormatR32Sint, TextureFormatR32Float,
+		TextureFormatRG32Uint, TextureFormatRG32Sint, TextureFormatRG32Float,
+		TextureFormatRGBA32Uint, TextureFormatRGBA32Sint, TextureFormatRGBA32Float:
+		return true
+	case TextureFormatBGRA8Unorm:
+		return access != StorageTextureAccessReadOnly && features.Contains(FeatureBGRA8UnormStorage)
+	}
+	return false
+}
+
+// SampleType returns the sample type of f when viewed with the given
+// aspect: Float for filterable formats, UnfilterableFloat, Depth, Sint or
+// Uint. It returns Undefined if the view cannot be sampled, such as a
+// combined depth/stencil format viewed with TextureAspectAll.
+//
+// The 32-bit float formats are filterable with FeatureFloat32Filterable.
+// Depth formats also accept UnfilterableFloat bindings; see
+// TextureSampleType.Accepts.
+func (f TextureFormat) SampleType(aspect TextureAspect, features Features) TextureSampleType {
+	if f.IsDepthStencil() {
+		depth, stencil := f.HasDepth(), f.HasStencil()
+		switch {
+		case aspect == TextureAspectDepthOnly && depth,
+			(aspect == TextureAspectAll || aspect == TextureAspectUndefined) && !stencil:
+			return TextureSampleTypeDepth
+		case aspect == TextureAspectStencilOnly && stencil,
+			(aspect == TextureAspectAll || aspect == TextureAspectUndefined) && !depth:
+			return TextureSampleTypeUint
+		}
+		return TextureSampleTypeUndefined
+	}
+	if aspect != TextureAspectAll && aspect != TextureAspectUndefined {
+		return TextureSampleTypeUndefined
+	}
+
+	switch f {
+	case TextureFormatUndefined:
+		return TextureSampleTypeUndefined
+	case TextureFormatR8Uint, TextureFormatR16Uint, TextureFormatRG8Uint, TextureFormatR32Uint,
+		TextureFormatRG16Uint, TextureFormatRGBA8Uint, TextureFormatRGB10A2Uint, TextureFormatRG32Uint,
+		TextureFormatRGBA16Uint, TextureFormatRGBA32Uint:
+		return TextureSampleTypeUint
+	case TextureFormatR8Sint, TextureFormatR16Sint, TextureFormatRG8Sint, TextureFormatR32Sint,
+		TextureFormatRG16Sint, TextureFormatRGBA8Sint, TextureFormatRG32Sint, TextureFormatRGBA16Sint,
+		TextureFormatRGBA32Sint:
+		return TextureSampleTypeSint
+	case TextureFormatR32Float, TextureFormatRG32Float, TextureFormatRGBA32Float:
+		if features.Contains(FeatureFloat32Filterable) {
+			return TextureSampleTypeFloat
+		}
+		return TextureSampleTypeUnfilterableFloat
+	}
+	return TextureSampleTypeFloat
+}
+
 // TextureDimension describes texture dimensions.
 type TextureDimension uint32
 
@@ -893,6 +1071,26 @@ func (t TextureSampleType) String() string {
 	}
 }
 
+// Accepts reports whether a texture binding with sample type t accepts a
+// view whose format has sample type view (see TextureFormat.SampleType).
+// Undefined is read as Float. Filterable views also bind as
+// UnfilterableFloat, and depth views bind as Depth or UnfilterableFloat.
+func (t TextureSampleType) Accepts(view TextureSampleType) bool {
+	if t == TextureSampleTypeUndefined {
+		t = TextureSampleTypeFloat
+	}
+	switch view {
+	case TextureSampleTypeFloat:
+		return t == TextureSampleTypeFloat || t == TextureSampleTypeUnfilterableFloat
+	case TextureSampleTypeDepth:
+		return t == TextureSampleTypeDepth || t == TextureSampleTypeUnfilterableFloat
+	case TextureSampleTypeUndefined:
+		return false
+	default:
+		return t == view
+	}
+}
+
 // ImageCopyTexture describes a texture copy source or destination.
 type ImageCopyTexture struct {
 	// Texture is a handle to the texture (implementation-specific).
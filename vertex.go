@@ -4,73 +4,95 @@ package gputypes
 //
 // The format name follows the pattern: TypeSizex[Count]
 // where Type is the data type, Size is the bit width, and Count is the number of components.
+// Values match WGPUVertexFormat in webgpu.h.
 type VertexFormat uint32
 
 const (
 	// VertexFormatUndefined is an undefined vertex format (invalid).
 	VertexFormatUndefined VertexFormat = 0x00000000
+	// VertexFormatUint8 is a single 8-bit unsigned integer.
+	VertexFormatUint8 VertexFormat = 0x00000001
 	// VertexFormatUint8x2 is two 8-bit unsigned integers.
-	VertexFormatUint8x2 VertexFormat = 0x00000001
+	VertexFormatUint8x2 VertexFormat = 0x00000002
 	// VertexFormatUint8x4 is four 8-bit unsigned integers.
-	VertexFormatUint8x4 VertexFormat = 0x00000002
+	VertexFormatUint8x4 VertexFormat = 0x00000003
+	// VertexFormatSint8 is a single 8-bit signed integer.
+	VertexFormatSint8 VertexFormat = 0x00000004
 	// VertexFormatSint8x2 is two 8-bit signed integers.
-	VertexFormatSint8x2 VertexFormat = 0x00000003
+	VertexFormatSint8x2 VertexFormat = 0x00000005
 	// VertexFormatSint8x4 is four 8-bit signed integers.
-	VertexFormatSint8x4 VertexFormat = 0x00000004
+	VertexFormatSint8x4 VertexFormat = 0x00000006
+	// VertexFormatUnorm8 is a single 8-bit normalized unsigned integer [0.0, 1.0].
+	VertexFormatUnorm8 VertexFormat = 0x00000007
 	// VertexFormatUnorm8x2 is two 8-bit normalized unsigned integers [0.0, 1.0].
-	VertexFormatUnorm8x2 VertexFormat = 0x00000005
+	VertexFormatUnorm8x2 VertexFormat = 0x00000008
 	// VertexFormatUnorm8x4 is four 8-bit normalized unsigned integers [0.0, 1.0].
-	VertexFormatUnorm8x4 VertexFormat = 0x00000006
+	VertexFormatUnorm8x4 VertexFormat = 0x00000009
+	// VertexFormatSnorm8 is a single 8-bit normalized signed integer [-1.0, 1.0].
+	VertexFormatSnorm8 VertexFormat = 0x0000000A
 	// VertexFormatSnorm8x2 is two 8-bit normalized signed integers [-1.0, 1.0].
-	VertexFormatSnorm8x2 VertexFormat = 0x00000007
+	VertexFormatSnorm8x2 VertexFormat = 0x0000000B
 	// VertexFormatSnorm8x4 is four 8-bit normalized signed integers [-1.0, 1.0].
-	VertexFormatSnorm8x4 VertexFormat = 0x00000008
+	VertexFormatSnorm8x4 VertexFormat = 0x0000000C
+	// VertexFormatUint16 is a single 16-bit unsigned integer.
+	VertexFormatUint16 VertexFormat = 0x0000000D
 	// VertexFormatUint16x2 is two 16-bit unsigned integers.
-	VertexFormatUint16x2 VertexFormat = 0x00000009
+	VertexFormatUint16x2 VertexFormat = 0x0000000E
 	// VertexFormatUint16x4 is four 16-bit unsigned integers.
-	VertexFormatUint16x4 VertexFormat = 0x0000000A
+	VertexFormatUint16x4 VertexFormat = 0x0000000F
+	// VertexFormatSint16 is a single 16-bit signed integer.
+	VertexFormatSint16 VertexFormat = 0x00000010
 	// VertexFormatSint16x2 is two 16-bit signed integers.
-	VertexFormatSint16x2 VertexFormat = 0x0000000B
+	VertexFormatSint16x2 VertexFormat = 0x00000011
 	// VertexFormatSint16x4 is four 16-bit signed integers.
-	VertexFormatSint16x4 VertexFormat = 0x0000000C
+	VertexFormatSint16x4 VertexFormat = 0x00000012
+	// VertexFormatUnorm16 is a single 16-bit normalized unsigned integer [0.0, 1.0].
+	VertexFormatUnorm16 VertexFormat = 0x00000013
 	// VertexFormatUnorm16x2 is two 16-bit normalized unsigned integers [0.0, 1.0].
-	VertexFormatUnorm16x2 VertexFormat = 0x0000000D
+	VertexFormatUnorm16x2 VertexFormat = 0x00000014
 	// VertexFormatUnorm16x4 is four 16-bit normalized unsigned integers [0.0, 1.0].
-	VertexFormatUnorm16x4 VertexFormat = 0x0000000E
+	VertexFormatUnorm16x4 VertexFormat = 0x00000015
+	// VertexFormatSnorm16 is a single 16-bit normalized signed integer [-1.0, 1.0].
+	VertexFormatSnorm16 VertexFormat = 0x00000016
 	// VertexFormatSnorm16x2 is two 16-bit normalized signed integers [-1.0, 1.0].
-	VertexFormatSnorm16x2 VertexFormat = 0x0000000F
+	VertexFormatSnorm16x2 VertexFormat = 0x00000017
 	// VertexFormatSnorm16x4 is four 16-bit normalized signed integers [-1.0, 1.0].
-	VertexFormatSnorm16x4 VertexFormat = 0x00000010
+	VertexFormatSnorm16x4 VertexFormat = 0x00000018
+	// VertexFormatFloat16 is a single 16-bit float.
+	VertexFormatFloat16 VertexFormat = 0x00000019
 	// VertexFormatFloat16x2 is two 16-bit floats.
-	VertexFormatFloat16x2 VertexFormat = 0x00000011
+	VertexFormatFloat16x2 VertexFormat = 0x0000001A
 	// VertexFormatFloat16x4 is four 16-bit floats.
-	VertexFormatFloat16x4 VertexFormat = 0x00000012
+	VertexFormatFloat16x4 VertexFormat = 0x0000001B
 	// VertexFormatFloat32 is a single 32-bit float.
-	VertexFormatFloat32 VertexFormat = 0x00000013
+	VertexFormatFloat32 VertexFormat = 0x0000001C
 	// VertexFormatFloat32x2 is two 32-bit floats (vec2).
-	VertexFormatFloat32x2 VertexFormat = 0x00000014
+	VertexFormatFloat32x2 VertexFormat = 0x0000001D
 	// VertexFormatFloat32x3 is three 32-bit floats (vec3).
-	VertexFormatFloat32x3 VertexFormat = 0x00000015
+	VertexFormatFloat32x3 VertexFormat = 0x0000001E
 	// VertexFormatFloat32x4 is four 32-bit floats (vec4).
-	VertexFormatFloat32x4 VertexFormat = 0x00000016
+	VertexFormatFloat32x4 VertexFormat = 0x0000001F
 	// VertexFormatUint32 is a single 32-bit unsigned integer.
-	VertexFormatUint32 VertexFormat = 0x00000017
+	VertexFormatUint32 VertexFormat = 0x00000020
 	// VertexFormatUint32x2 is two 32-bit unsigned integers.
-	VertexFormatUint32x2 VertexFormat = 0x00000018
+	VertexFormatUint32x2 VertexFormat = 0x00000021
 	// VertexFormatUint32x3 is three 32-bit unsigned integers.
-	VertexFormatUint32x3 VertexFormat = 0x00000019
+	VertexFormatUint32x3 VertexFormat = 0x00000022
 	// VertexFormatUint32x4 is four 32-bit unsigned integers.
-	VertexFormatUint32x4 VertexFormat = 0x0000001A
+	VertexFormatUint32x4 VertexFormat = 0x00000023
 	// VertexFormatSint32 is a single 32-bit signed integer.
-	VertexFormatSint32 VertexFormat = 0x0000001B
+	VertexFormatSint32 VertexFormat = 0x00000024
 	// VertexFormatSint32x2 is two 32-bit signed integers.
-	VertexFormatSint32x2 VertexFormat = 0x0000001C
+	VertexFormatSint32x2 VertexFormat = 0x00000025
 	// VertexFormatSint32x3 is three 32-bit signed integers.
-	VertexFormatSint32x3 VertexFormat = 0x0000001D
+	VertexFormatSint32x3 VertexFormat = 0x00000026
 	// VertexFormatSint32x4 is four 32-bit signed integers.
-	VertexFormatSint32x4 VertexFormat = 0x0000001E
+	VertexFormatSint32x4 VertexFormat = 0x00000027
 	// VertexFormatUnorm1010102 is a packed 10-10-10-2 normalized unsigned format.
-	VertexFormatUnorm1010102 VertexFormat = 0x0000001F
+	VertexFormatUnorm1010102 VertexFormat = 0x00000028
+	// VertexFormatUnorm8x4BGRA is four 8-bit normalized unsigned integers stored
+	// in BGRA order and swizzled to RGBA when read by the shader.
+	VertexFormatUnorm8x4BGRA VertexFormat = 0x00000029
 )
 
 // String returns the vertex format name.
@@ -78,38 +100,56 @@ func (f VertexFormat) String() string {
 	switch f {
 	case VertexFormatUndefined:
 		return "Undefined"
+	case VertexFormatUint8:
+		return "Uint8"
 	case VertexFormatUint8x2:
 		return "Uint8x2"
 	case VertexFormatUint8x4:
 		return "Uint8x4"
+	case VertexFormatSint8:
+		return "Sint8"
 	case VertexFormatSint8x2:
 		return "Sint8x2"
 	case VertexFormatSint8x4:
 		return "Sint8x4"
+	case VertexFormatUnorm8:
+		return "Unorm8"
 	case VertexFormatUnorm8x2:
 		return "Unorm8x2"
 	case VertexFormatUnorm8x4:
 		return "Unorm8x4"
+	case VertexFormatSnorm8:
+		return "Snorm8"
 	case VertexFormatSnorm8x2:
 		return "Snorm8x2"
 	case VertexFormatSnorm8x4:
 		return "Snorm8x4"
+	case VertexFormatUint16:
+		return "Uint16"
 	case VertexFormatUint16x2:
 		return "Uint16x2"
 	case VertexFormatUint16x4:
 		return "Uint16x4"
+	case VertexFormatSint16:
+		return "Sint16"
 	case VertexFormatSint16x2:
 		return "Sint16x2"
 	case VertexFormatSint16x4:
 		return "Sint16x4"
+	case VertexFormatUnorm16:
+		return "Unorm16"
 	case VertexFormatUnorm16x2:
 		return "Unorm16x2"
 	case VertexFormatUnorm16x4:
 		return "Unorm16x4"
+	case VertexFormatSnorm16:
+		return "Snorm16"
 	case VertexFormatSnorm16x2:
 		return "Snorm16x2"
 	case VertexFormatSnorm16x4:
 		return "Snorm16x4"
+	case VertexFormatFloat16:
+		return "Float16"
 	case VertexFormatFloat16x2:
 		return "Float16x2"
 	case VertexFormatFloat16x4:
@@ -140,20 +180,119 @@ func (f VertexFormat) String() string {
 		return "Sint32x4"
 	case VertexFormatUnorm1010102:
 		return "Unorm1010102"
+	case VertexFormatUnorm8x4BGRA:
+		return "Unorm8x4BGRA"
 	default:
 		return "Unknown"
 	}
 }
 
+// SpecName returns the vertex format name as written in the WebGPU
+// specification (GPUVertexFormat), e.g. "unorm8x4-bgra" or "unorm10-10-10-2".
+//
+// Returns an empty string for unknown or invalid formats.
+func (f VertexFormat) SpecName() string {
+	switch f {
+	case VertexFormatUint8:
+		return "uint8"
+	case VertexFormatUint8x2:
+		return "uint8x2"
+	case VertexFormatUint8x4:
+		return "uint8x4"
+	case VertexFormatSint8:
+		return "sint8"
+	case VertexFormatSint8x2:
+		return "sint8x2"
+	case VertexFormatSint8x4:
+		return "sint8x4"
+	case VertexFormatUnorm8:
+		return "unorm8"
+	case VertexFormatUnorm8x2:
+		return "unorm8x2"
+	case VertexFormatUnorm8x4:
+		return "unorm8x4"
+	case VertexFormatSnorm8:
+		return "snorm8"
+	case VertexFormatSnorm8x2:
+		return "snorm8x2"
+	case VertexFormatSnorm8x4:
+		return "snorm8x4"
+	case VertexFormatUint16:
+		return "uint16"
+	case VertexFormatUint16x2:
+		return "uint16x2"
+	case VertexFormatUint16x4:
+		return "uint16x4"
+	case VertexFormatSint16:
+		return "sint16"
+	case VertexFormatSint16x2:
+		return "sint16x2"
+	case VertexFormatSint16x4:
+		return "sint16x4"
+	case VertexFormatUnorm16:
+		return "unorm16"
+	case VertexFormatUnorm16x2:
+		return "unorm16x2"
+	case VertexFormatUnorm16x4:
+		return "unorm16x4"
+	case VertexFormatSnorm16:
+		return "snorm16"
+	case VertexFormatSnorm16x2:
+		return "snorm16x2"
+	case VertexFormatSnorm16x4:
+		return "snorm16x4"
+	case VertexFormatFloat16:
+		return "float16"
+	case VertexFormatFloat16x2:
+		return "float16x2"
+	case VertexFormatFloat16x4:
+		return "float16x4"
+	case VertexFormatFloat32:
+		return "float32"
+	case VertexFormatFloat32x2:
+		return "float32x2"
+	case VertexFormatFloat32x3:
+		return "float32x3"
+	case VertexFormatFloat32x4:
+		return "float32x4"
+	case VertexFormatUint32:
+		return "uint32"
+	case VertexFormatUint32x2:
+		return "uint32x2"
+	case VertexFormatUint32x3:
+		return "uint32x3"
+	case VertexFormatUint32x4:
+		return "uint32x4"
+	case VertexFormatSint32:
+		return "sint32"
+	case VertexFormatSint32x2:
+		return "sint32x2"
+	case VertexFormatSint32x3:
+		return "sint32x3"
+	case VertexFormatSint32x4:
+		return "sint32x4"
+	case VertexFormatUnorm1010102:
+		return "unorm10-10-10-2"
+	case VertexFormatUnorm8x4BGRA:
+		return "unorm8x4-bgra"
+	default:
+		return ""
+	}
+}
+
 // Size returns the byte size of the vertex format.
 func (f VertexFormat) Size() uint64 {
 	switch f {
-	case VertexFormatUint8x2, VertexFormatSint8x2, VertexFormatUnorm8x2, VertexFormatSnorm8x2:
+	case VertexFormatUint8, VertexFormatSint8, VertexFormatUnorm8, VertexFormatSnorm8:
+		return 1
+	case VertexFormatUint8x2, VertexFormatSint8x2, VertexFormatUnorm8x2, VertexFormatSnorm8x2,
+		VertexFormatUint16, VertexFormatSint16, VertexFormatUnorm16, VertexFormatSnorm16,
+		VertexFormatFloat16:
 		return 2
 	case VertexFormatUint8x4, VertexFormatSint8x4, VertexFormatUnorm8x4, VertexFormatSnorm8x4,
 		VertexFormatUint16x2, VertexFormatSint16x2, VertexFormatUnorm16x2, VertexFormatSnorm16x2,
 		VertexFormatFloat16x2, VertexFormatFloat32, VertexFormatUint32, VertexFormatSint32,
-		VertexFormatUnorm1010102:
+		VertexFormatUnorm1010102, VertexFormatUnorm8x4BGRA:
 		return 4
 	case VertexFormatUint16x4, VertexFormatSint16x4, VertexFormatUnorm16x4, VertexFormatSnorm16x4,
 		VertexFormatFloat16x4, VertexFormatFloat32x2, VertexFormatUint32x2, VertexFormatSint32x2:
@@ -167,6 +306,176 @@ func (f VertexFormat) Size() uint64 {
 	}
 }
 
+// ComponentCount returns the number of components in the vertex format.
+//
+// Packed formats report their logical component count (4 for Unorm1010102).
+// Returns 0 for unknown or invalid formats.
+func (f VertexFormat) ComponentCount() uint32 {
+	switch f {
+	case VertexFormatUint8, VertexFormatSint8, VertexFormatUnorm8, VertexFormatSnorm8,
+		VertexFormatUint16, VertexFormatSint16, VertexFormatUnorm16, VertexFormatSnorm16,
+		VertexFormatFloat16, VertexFormatFloat32, VertexFormatUint32, VertexFormatSint32:
+		return 1
+	case VertexFormatUint8x2, VertexFormatSint8x2, VertexFormatUnorm8x2, VertexFormatSnorm8x2,
+		VertexFormatUint16x2, VertexFormatSint16x2, VertexFormatUnorm16x2, VertexFormatSnorm16x2,
+		VertexFormatFloat16x2, VertexFormatFloat32x2, VertexFormatUint32x2, VertexFormatSint32x2:
+		return 2
+	case VertexFormatFloat32x3, VertexFormatUint32x3, VertexFormatSint32x3:
+		return 3
+	case VertexFormatUint8x4, VertexFormatSint8x4, VertexFormatUnorm8x4, VertexFormatSnorm8x4,
+		VertexFormatUint16x4, VertexFormatSint16x4, VertexFormatUnorm16x4, VertexFormatSnorm16x4,
+		VertexFormatFloat16x4, VertexFormatFloat32x4, VertexFormatUint32x4, VertexFormatSint32x4,
+		VertexFormatUnorm1010102, VertexFormatUnorm8x4BGRA:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// ComponentType returns the storage type of each component in the vertex format.
+//
+// Normalized formats report their underlying integer type; use IsNormalized
+// to distinguish Unorm8x4 from Uint8x4.
+func (f VertexFormat) ComponentType() VertexComponentType {
+	switch f {
+	case VertexFormatUint8, VertexFormatUint8x2, VertexFormatUint8x4,
+		VertexFormatUnorm8, VertexFormatUnorm8x2, VertexFormatUnorm8x4, VertexFormatUnorm8x4BGRA:
+		return VertexComponentTypeUint8
+	case VertexFormatSint8, VertexFormatSint8x2, VertexFormatSint8x4,
+		VertexFormatSnorm8, VertexFormatSnorm8x2, VertexFormatSnorm8x4:
+		return VertexComponentTypeSint8
+	case VertexFormatUint16, VertexFormatUint16x2, VertexFormatUint16x4,
+		VertexFormatUnorm16, VertexFormatUnorm16x2, VertexFormatUnorm16x4:
+		return VertexComponentTypeUint16
+	case VertexFormatSint16, VertexFormatSint16x2, VertexFormatSint16x4,
+		VertexFormatSnorm16, VertexFormatSnorm16x2, VertexFormatSnorm16x4:
+		return VertexComponentTypeSint16
+	case VertexFormatFloat16, VertexFormatFloat16x2, VertexFormatFloat16x4:
+		return VertexComponentTypeFloat16
+	case VertexFormatFloat32, VertexFormatFloat32x2, VertexFormatFloat32x3, VertexFormatFloat32x4:
+		return VertexComponentTypeFloat32
+	case VertexFormatUint32, VertexFormatUint32x2, VertexFormatUint32x3, VertexFormatUint32x4:
+		return VertexComponentTypeUint32
+	case VertexFormatSint32, VertexFormatSint32x2, VertexFormatSint32x3, VertexFormatSint32x4:
+		return VertexComponentTypeSint32
+	case VertexFormatUnorm1010102:
+		return VertexComponentTypePacked1010102
+	default:
+		return VertexComponentTypeUndefined
+	}
+}
+
+// IsNormalized returns true if integer components are normalized to
+// [0.0, 1.0] (unorm) or [-1.0, 1.0] (snorm) when read by the shader.
+func (f VertexFormat) IsNormalized() bool {
+	switch f {
+	case VertexFormatUnorm8, VertexFormatUnorm8x2, VertexFormatUnorm8x4,
+		VertexFormatSnorm8, VertexFormatSnorm8x2, VertexFormatSnorm8x4,
+		VertexFormatUnorm16, VertexFormatUnorm16x2, VertexFormatUnorm16x4,
+		VertexFormatSnorm16, VertexFormatSnorm16x2, VertexFormatSnorm16x4,
+		VertexFormatUnorm1010102, VertexFormatUnorm8x4BGRA:
+		return true
+	default:
+		return false
+	}
+}
+
+// WGSLType returns the WGSL type a shader input must use to consume this format,
+// e.g. "vec4<f32>" for Unorm8x4 or "vec3<u32>" for Uint32x3.
+//
+// Float and normalized formats map to f32, unsigned integers to u32 and signed
+// integers to i32. Returns an empty string for unknown or invalid formats.
+func (f VertexFormat) WGSLType() string {
+	var scalar string
+	switch f.ComponentType() {
+	case VertexComponentTypeUndefined:
+		return ""
+	case VertexComponentTypeUint8, VertexComponentTypeUint16, VertexComponentTypeUint32:
+		scalar = "u32"
+	case VertexComponentTypeSint8, VertexComponentTypeSint16, VertexComponentTypeSint32:
+		scalar = "i32"
+	default:
+		scalar = "f32"
+	}
+	if f.IsNormalized() {
+		scalar = "f32"
+	}
+
+	switch f.ComponentCount() {
+	case 2:
+		return "vec2<" + scalar + ">"
+	case 3:
+		return "vec3<" + scalar + ">"
+	case 4:
+		return "vec4<" + scalar + ">"
+	default:
+		return scalar
+	}
+}
+
+// Alignment returns the minimum required byte alignment of an attribute
+// offset using this format: min(4, Size()).
+//
+// Returns 0 for unknown or invalid formats.
+func (f VertexFormat) Alignment() uint64 {
+	return min(4, f.Size())
+}
+
+// VertexComponentType describes the storage type of a single vertex format component.
+type VertexComponentType uint32
+
+const (
+	// VertexComponentTypeUndefined is an undefined component type (invalid).
+	VertexComponentTypeUndefined VertexComponentType = iota
+	// VertexComponentTypeUint8 is an 8-bit unsigned integer.
+	VertexComponentTypeUint8
+	// VertexComponentTypeSint8 is an 8-bit signed integer.
+	VertexComponentTypeSint8
+	// VertexComponentTypeUint16 is a 16-bit unsigned integer.
+	VertexComponentTypeUint16
+	// VertexComponentTypeSint16 is a 16-bit signed integer.
+	VertexComponentTypeSint16
+	// VertexComponentTypeFloat16 is a 16-bit IEEE 754 half-precision float.
+	VertexComponentTypeFloat16
+	// VertexComponentTypeFloat32 is a 32-bit IEEE 754 float.
+	VertexComponentTypeFloat32
+	// VertexComponentTypeUint32 is a 32-bit unsigned integer.
+	VertexComponentTypeUint32
+	// VertexComponentTypeSint32 is a 32-bit signed integer.
+	VertexComponentTypeSint32
+	// VertexComponentTypePacked1010102 is three 10-bit and one 2-bit unsigned
+	// integer packed into 32 bits.
+	VertexComponentTypePacked1010102
+)
+
+// String returns the component type name.
+func (t VertexComponentType) String() string {
+	switch t {
+	case VertexComponentTypeUndefined:
+		return "Undefined"
+	case VertexComponentTypeUint8:
+		return "Uint8"
+	case VertexComponentTypeSint8:
+		return "Sint8"
+	case VertexComponentTypeUint16:
+		return "Uint16"
+	case VertexComponentTypeSint16:
+		return "Sint16"
+	case VertexComponentTypeFloat16:
+		return "Float16"
+	case VertexComponentTypeFloat32:
+		return "Float32"
+	case VertexComponentTypeUint32:
+		return "Uint32"
+	case VertexComponentTypeSint32:
+		return "Sint32"
+	case VertexComponentTypePacked1010102:
+		return "Packed1010102"
+	default:
+		return "Unknown"
+	}
+}
+
 // VertexStepMode describes how vertex data is stepped.
 type VertexStepMode uint32
 
@@ -0,0 +1,519 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// EncodeFloat32 writes one attribute value in format f to dst.
+//
+// values must hold exactly ComponentCount() components, in the order the
+// shader sees them (RGBA for Unorm8x4BGRA). Float formats store the values
+// directly, converting to half precision for Float16 formats with
+// round-to-nearest-even. Normalized formats clamp to [0, 1] (unorm) or
+// [-1, 1] (snorm) and round to the nearest representable value. Integer
+// formats round to the nearest integer and clamp to the storage range.
+// NaN is stored as 0 in integer and normalized formats.
+//
+// dst must be at least Size() bytes long.
+func (f VertexFormat) EncodeFloat32(dst []byte, values []float32) error {
+	if err := f.checkVertexData(len(dst), len(values)); err != nil {
+		return err
+	}
+
+	if f == VertexFormatUnorm1010102 {
+		var packed uint32
+		for i, v := range values {
+			packed |= uint32(math.Round(float64(clamp01(v))*float64(packed1010102Max[i]))) << packed1010102Shift[i]
+		}
+		binary.LittleEndian.PutUint32(dst, packed)
+		return nil
+	}
+
+	ct := f.ComponentType()
+	size := ct.size()
+	lo, hi := ct.intRange()
+	for i, v := range values {
+		b := dst[f.componentIndex(i)*size:]
+		switch {
+		case ct == VertexComponentTypeFloat32:
+			binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+		case ct == VertexComponentTypeFloat16:
+			binary.LittleEndian.PutUint16(b, float32ToHalf(v))
+		case f.IsNormalized() && lo == 0:
+			putVertexInt(b, size, int64(math.Round(float64(clamp01(v))*float64(hi))))
+		case f.IsNormalized():
+			putVertexInt(b, size, int64(math.Round(float64(max(-1, min(1, nanToZero(v))))*float64(hi))))
+		default:
+			putVertexInt(b, size, int64(max(float64(lo), min(float64(hi), math.Round(float64(nanToZero(v)))))))
+		}
+	}
+	return nil
+}
+
+// DecodeFloat32 reads one attribute value in format f from src into values.
+//
+// It is the inverse of EncodeFloat32 and returns components in the order the
+// shader sees them. Normalized formats are converted as the GPU does: unorm
+// values divide by the maximum, snorm values divide by the maximum and clamp
+// to -1. Integer formats are converted to the nearest float32.
+//
+// src must be at least Size() bytes long and values must have room for
+// ComponentCount() components; extra elements are left untouched.
+func (f VertexFormat) DecodeFloat32(src []byte, values []float32) error {
+	n := int(f.ComponentCount())
+	if len(values) < n {
+		return fmt.Errorf("gputypes: %s needs %d components, got room for %d", f, n, len(values))
+	}
+	if err := f.checkVertexData(len(src), n); err != nil {
+		return err
+	}
+
+	if f == VertexFormatUnorm1010102 {
+		packed := binary.LittleEndian.Uint32(src)
+		for i := range n {
+			raw := packed >> packed1010102Shift[i] & packed1010102Max[i]
+			values[i] = float32(raw) / float32(packed1010102Max[i])
+		}
+		return nil
+	}
+
+	ct := f.ComponentType()
+	size := ct.size()
+	lo, hi := ct.intRange()
+	for i := range n {
+		b := src[f.componentIndex(i)*size:]
+		switch {
+		case ct == VertexComponentTypeFloat32:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case ct == VertexComponentTypeFloat16:
+			values[i] = halfToFloat32(binary.LittleEndian.Uint16(b))
+		case f.IsNormalized() && lo == 0:
+			values[i] = float32(float64(getVertexInt(b, size, false)) / float64(hi))
+		case f.IsNormalized():
+			values[i] = float32(max(-1, float64(getVertexInt(b, size, true))/float64(hi)))
+		default:
+			values[i] = float32(getVertexInt(b, size, lo < 0))
+		}
+	}
+	return nil
+}
+
+// EncodeUint32 writes the raw unsigned integer components of one attribute
+// value in format f to dst.
+//
+// It is valid for unsigned integer formats and for unorm formats, where it
+// writes the stored integer without normalization (e.g. 255 for 1.0 in
+// Unorm8x4). Values that do not fit the storage type are rejected.
+func (f VertexFormat) EncodeUint32(dst []byte, values []uint32) error {
+	if err := f.checkVertexData(len(dst), len(values)); err != nil {
+		return err
+	}
+	if !f.isUnsignedInt() {
+		return fmt.Errorf("gputypes: %w: %s does not store unsigned integers", ErrVertexFormat, f)
+	}
+
+	if f == VertexFormatUnorm1010102 {
+		var packed uint32
+		for i, v := range values {
+			if v > packed1010102Max[i] {
+				return fmt.Errorf("gputypes: %s component %d value %d exceeds %d", f, i, v, packed1010102Max[i])
+			}
+			packed |= v << packed1010102Shift[i]
+		}
+		binary.LittleEndian.PutUint32(dst, packed)
+		return nil
+	}
+
+	ct := f.ComponentType()
+	size := ct.size()
+	_, hi := ct.intRange()
+	for i, v := range values {
+		if int64(v) > hi {
+			return fmt.Errorf("gputypes: %s component %d value %d exceeds %d", f, i, v, hi)
+		}
+		putVertexInt(dst[f.componentIndex(i)*size:], size, int64(v))
+	}
+	return nil
+}
+
+// DecodeUint32 reads the raw unsigned integer components of one attribute
+// value in format f from src into values. It is the inverse of EncodeUint32.
+func (f VertexFormat) DecodeUint32(src []byte, values []uint32) error {
+	n := int(f.ComponentCount())
+	if len(values) < n {
+		return fmt.Errorf("gputypes: %s needs %d components, got room for %d", f, n, len(values))
+	}
+	if err := f.checkVertexData(len(src), n); err != nil {
+		return err
+	}
+	if !f.isUnsignedInt() {
+		return fmt.Errorf("gputypes: %w: %s does not store unsigned integers", ErrVertexFormat, f)
+	}
+
+	if f == VertexFormatUnorm1010102 {
+		packed := binary.LittleEndian.Uint32(src)
+		for i := range n {
+			values[i] = packed >> packed1010102Shift[i] & packed1010102Max[i]
+		}
+		return nil
+	}
+
+	size := f.ComponentType().size()
+	for i := range n {
+		values[i] = uint32(getVertexInt(src[f.componentIndex(i)*size:], size, false))
+	}
+	return nil
+}
+
+// EncodeInt32 writes the raw signed integer components of one attribute
+// value in format f to dst.
+//
+// It is valid for signed integer formats and for snorm formats, where it
+// writes the stored integer without normalization (e.g. -127 for -1.0 in
+// Snorm8x4). Values that do not fit the storage type are rejected.
+func (f VertexFormat) EncodeInt32(dst []byte, values []int32) error {
+	if err := f.checkVertexData(len(dst), len(values)); err != nil {
+		return err
+	}
+	if !f.isSignedInt() {
+		return fmt.Errorf("gputypes: %w: %s does not store signed integers", ErrVertexFormat, f)
+	}
+
+	ct := f.ComponentType()
+	size := ct.size()
+	lo, hi := ct.intRange()
+	for i, v := range values {
+		if int64(v) < lo || int64(v) > hi {
+			return fmt.Errorf("gputypes: %s component %d value %d outside [%d, %d]", f, i, v, lo, hi)
+		}
+		putVertexInt(dst[i*size:], size, int64(v))
+	}
+	return nil
+}
+
+// DecodeInt32 reads the raw signed integer components of one attribute
+// value in format f from src into values. It is the inverse of EncodeInt32.
+func (f VertexFormat) DecodeInt32(src []byte, values []int32) error {
+	n := int(f.ComponentCount())
+	if len(values) < n {
+		return fmt.Errorf("gputypes: %s needs %d components, got room for %d", f, n, len(values))
+	}
+	if err := f.checkVertexData(len(src), n); err != nil {
+		return err
+	}
+	if !f.isSignedInt() {
+		return fmt.Errorf("gputypes: %w: %s does not store signed integers", ErrVertexFormat, f)
+	}
+
+	size := f.ComponentType().size()
+	for i := range n {
+		values[i] = int32(getVertexInt(src[i*size:], size, true))
+	}
+	return nil
+}
+
+// VertexAttributeView gives strided access to one attribute of an
+// interleaved vertex buffer.
+//
+// Element i lives at Data[Offset+i*Stride:]. A zero Stride makes every
+// element alias the first one, matching a zero ArrayStride in WebGPU.
+type VertexAttributeView struct {
+	// Data is the raw vertex buffer contents.
+	Data []byte
+	// Format is the attribute format.
+	Format VertexFormat
+	// Offset is the byte offset of the attribute within the first element.
+	Offset uint64
+	// Stride is the byte distance between consecutive elements.
+	Stride uint64
+}
+
+// AttributeView returns a view of the attribute bound to the given shader
+// location in data, which holds vertices laid out as described by l.
+func (l VertexBufferLayout) AttributeView(data []byte, location uint32) (VertexAttributeView, error) {
+	for _, attr := range l.Attributes {
+		if attr.ShaderLocation == location {
+			return VertexAttributeView{
+				Data:   data,
+				Format: attr.Format,
+				Offset: attr.Offset,
+				Stride: l.ArrayStride,
+			}, nil
+		}
+	}
+	return VertexAttributeView{}, fmt.Errorf("gputypes: vertex buffer layout has no attribute at location %d", location)
+}
+
+// Len returns the number of complete elements in the view.
+func (v VertexAttributeView) Len() int {
+	size := v.Format.Size()
+	n := uint64(len(v.Data))
+	if size == 0 || v.Offset > n || size > n-v.Offset {
+		return 0
+	}
+	if v.Stride == 0 {
+		return 1
+	}
+	return int((n-v.Offset-size)/v.Stride + 1)
+}
+
+// element returns the bytes of element i.
+func (v VertexAttributeView) element(i int) ([]byte, error) {
+	if i < 0 || i >= v.Len() {
+		return nil, fmt.Errorf("gputypes: vertex attribute index %d out of range [0, %d)", i, v.Len())
+	}
+	start := v.Offset + uint64(i)*v.Stride
+	return v.Data[start : start+v.Format.Size()], nil
+}
+
+// SetFloat32 encodes values into element i. See VertexFormat.EncodeFloat32.
+func (v VertexAttributeView) SetFloat32(i int, values ...float32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.EncodeFloat32(b, values)
+}
+
+// Float32 decodes element i into values. See VertexFormat.DecodeFloat32.
+func (v VertexAttributeView) Float32(i int, values []float32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.DecodeFloat32(b, values)
+}
+
+// SetUint32 encodes raw unsigned values into element i. See VertexFormat.EncodeUint32.
+func (v VertexAttributeView) SetUint32(i int, values ...uint32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.EncodeUint32(b, values)
+}
+
+// Uint32 decodes raw unsigned values from element i. See VertexFormat.DecodeUint32.
+func (v VertexAttributeView) Uint32(i int, values []uint32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.DecodeUint32(b, values)
+}
+
+// SetInt32 encodes raw signed values into element i. See VertexFormat.EncodeInt32.
+func (v VertexAttributeView) SetInt32(i int, values ...int32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.EncodeInt32(b, values)
+}
+
+// Int32 decodes raw signed values from element i. See VertexFormat.DecodeInt32.
+func (v VertexAttributeView) Int32(i int, values []int32) error {
+	b, err := v.element(i)
+	if err != nil {
+		return err
+	}
+	return v.Format.DecodeInt32(b, values)
+}
+
+// Unorm1010102 layout: R, G, B in the low 30 bits, A in the top 2.
+var (
+	packed1010102Shift = [4]uint32{0, 10, 20, 30}
+	packed1010102Max   = [4]uint32{0x3FF, 0x3FF, 0x3FF, 0x3}
+)
+
+// checkVertexData validates buffer and component counts for f.
+func (f VertexFormat) checkVertexData(bufLen, components int) error {
+	size := f.Size()
+	if size == 0 {
+		return fmt.Errorf("gputypes: %w: %s", ErrVertexFormat, f)
+	}
+	if components != int(f.ComponentCount()) {
+		return fmt.Errorf("gputypes: %s has %d components, got %d", f, f.ComponentCount(), components)
+	}
+	if uint64(bufLen) < size {
+		return fmt.Errorf("gputypes: %s needs %d bytes, got %d", f, size, bufLen)
+	}
+	return nil
+}
+
+// componentIndex maps shader component i to its storage slot.
+func (f VertexFormat) componentIndex(i int) int {
+	if f == VertexFormatUnorm8x4BGRA && i < 3 {
+		return 2 - i
+	}
+	return i
+}
+
+// isUnsignedInt reports whether f stores unsigned integers (raw or unorm).
+func (f VertexFormat) isUnsignedInt() bool {
+	switch f.ComponentType() {
+	case VertexComponentTypeUint8, VertexComponentTypeUint16, VertexComponentTypeUint32,
+		VertexComponentTypePacked1010102:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSignedInt reports whether f stores signed integers (raw or snorm).
+func (f VertexFormat) isSignedInt() bool {
+	switch f.ComponentType() {
+	case VertexComponentTypeSint8, VertexComponentTypeSint16, VertexComponentTypeSint32:
+		return true
+	default:
+		return false
+	}
+}
+
+// size returns the byte size of one component, or 0 for packed and undefined types.
+func (t VertexComponentType) size() int {
+	switch t {
+	case VertexComponentTypeUint8, VertexComponentTypeSint8:
+		return 1
+	case VertexComponentTypeUint16, VertexComponentTypeSint16, VertexComponentTypeFloat16:
+		return 2
+	case VertexComponentTypeUint32, VertexComponentTypeSint32, VertexComponentTypeFloat32:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// intRange returns the representable range of an integer component type.
+// For signed normalized types the minimum that maps to -1.0 is -hi.
+func (t VertexComponentType) intRange() (lo, hi int64) {
+	switch t {
+	case VertexComponentTypeUint8:
+		return 0, math.MaxUint8
+	case VertexComponentTypeSint8:
+		return math.MinInt8, math.MaxInt8
+	case VertexComponentTypeUint16:
+		return 0, math.MaxUint16
+	case VertexComponentTypeSint16:
+		return math.MinInt16, math.MaxInt16
+	case VertexComponentTypeUint32:
+		return 0, math.MaxUint32
+	case VertexComponentTypeSint32:
+		return math.MinInt32, math.MaxInt32
+	default:
+		return 0, 0
+	}
+}
+
+// putVertexInt stores the low size bytes of v in little-endian order.
+func putVertexInt(b []byte, size int, v int64) {
+	switch size {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(b, uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(b, uint32(v))
+	}
+}
+
+// getVertexInt loads a size-byte little-endian integer, sign-extending if signed.
+func getVertexInt(b []byte, size int, signed bool) int64 {
+	switch size {
+	case 1:
+		if signed {
+			return int64(int8(b[0]))
+		}
+		return int64(b[0])
+	case 2:
+		v := binary.LittleEndian.Uint16(b)
+		if signed {
+			return int64(int16(v))
+		}
+		return int64(v)
+	case 4:
+		v := binary.LittleEndian.Uint32(b)
+		if signed {
+			return int64(int32(v))
+		}
+		return int64(v)
+	default:
+		return 0
+	}
+}
+
+// clamp01 clamps v to [0, 1], mapping NaN to 0.
+func clamp01(v float32) float32 {
+	return max(0, min(1, nanToZero(v)))
+}
+
+// nanToZero maps NaN to 0 and returns other values unchanged.
+func nanToZero(v float32) float32 {
+	if v != v {
+		return 0
+	}
+	return v
+}
+
+// float32ToHalf converts f to IEEE 754 binary16 with round-to-nearest-even.
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23) & 0xFF
+	mant := bits & 0x7FFFFF
+
+	if exp == 0xFF { // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7E00
+		}
+		return sign | 0x7C00
+	}
+
+	e := exp - 127 + 15
+	if e >= 0x1F {
+		return sign | 0x7C00
+	}
+	if e <= 0 {
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - e)
+		h := mant >> shift
+		rem := mant & (1<<shift - 1)
+		half := uint32(1) << (shift - 1)
+		if rem > half || (rem == half && h&1 == 1) {
+			h++
+		}
+		return sign | uint16(h)
+	}
+
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1FFF
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		h++ // may carry into the exponent, up to Inf
+	}
+	return sign | uint16(h)
+}
+
+// halfToFloat32 converts an IEEE 754 binary16 value to float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0:
+		v := float32(mant) / (1 << 24)
+		if sign != 0 {
+			return -v
+		}
+		return v
+	case 0x1F:
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+112)<<23 | mant<<13)
+	}
+}
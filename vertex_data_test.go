@@ -0,0 +1,201 @@
+package gputypes
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestVertexFormat_EncodeFloat32(t *testing.T) {
+	tests := []struct {
+		name   string
+		format VertexFormat
+		values []float32
+		want   []byte
+	}{
+		{"Float32", VertexFormatFloat32, []float32{1}, []byte{0x00, 0x00, 0x80, 0x3F}},
+		{"Float16x2", VertexFormatFloat16x2, []float32{1, -2}, []byte{0x00, 0x3C, 0x00, 0xC0}},
+		{"Float16 rounds", VertexFormatFloat16, []float32{65520}, []byte{0x00, 0x7C}},
+		{"Unorm8x2 clamps", VertexFormatUnorm8x2, []float32{2, 0.5}, []byte{0xFF, 0x80}},
+		{"Snorm8x2", VertexFormatSnorm8x2, []float32{-1, 0.5}, []byte{0x81, 0x40}},
+		{"Unorm16", VertexFormatUnorm16, []float32{1}, []byte{0xFF, 0xFF}},
+		{"Sint16x2 clamps", VertexFormatSint16x2, []float32{-40000, 3.6}, []byte{0x00, 0x80, 0x04, 0x00}},
+		{"Uint8 NaN", VertexFormatUint8, []float32{float32(math.NaN())}, []byte{0x00}},
+		{"Unorm8x4BGRA", VertexFormatUnorm8x4BGRA, []float32{1, 0, 0, 1}, []byte{0x00, 0x00, 0xFF, 0xFF}},
+		{"Unorm1010102", VertexFormatUnorm1010102, []float32{1, 0, 1, 1}, []byte{0xFF, 0x03, 0xF0, 0xFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]byte, tt.format.Size())
+			if err := tt.format.EncodeFloat32(got, tt.values); err != nil {
+				t.Fatalf("EncodeFloat32() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeFloat32() = % x, want % x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVertexFormat_Float32RoundTrip(t *testing.T) {
+	for _, f := range allVertexFormats {
+		t.Run(f.String(), func(t *testing.T) {
+			n := f.ComponentCount()
+			in := []float32{0.25, 0.5, 1, 0}[:n]
+			if f.IsNormalized() && f.isSignedInt() {
+				in = []float32{0.25, -0.5, 1, 0}[:n]
+			}
+			buf := make([]byte, f.Size())
+			if err := f.EncodeFloat32(buf, in); err != nil {
+				t.Fatalf("EncodeFloat32() error = %v", err)
+			}
+			out := make([]float32, n)
+			if err := f.DecodeFloat32(buf, out); err != nil {
+				t.Fatalf("DecodeFloat32() error = %v", err)
+			}
+			for i := range out {
+				want := in[i]
+				if f.ComponentType() != VertexComponentTypeFloat32 && f.ComponentType() != VertexComponentTypeFloat16 && !f.IsNormalized() {
+					want = float32(math.Round(float64(want)))
+				}
+				if math.Abs(float64(out[i]-want)) > 1.0/127 {
+					t.Errorf("component %d = %v, want %v", i, out[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestVertexFormat_IntegerAccess(t *testing.T) {
+	buf := make([]byte, 4)
+	if err := VertexFormatUnorm1010102.EncodeUint32(buf, []uint32{1023, 0, 512, 3}); err != nil {
+		t.Fatalf("EncodeUint32() error = %v", err)
+	}
+	got := make([]uint32, 4)
+	if err := VertexFormatUnorm1010102.DecodeUint32(buf, got); err != nil {
+		t.Fatalf("DecodeUint32() error = %v", err)
+	}
+	if got[0] != 1023 || got[1] != 0 || got[2] != 512 || got[3] != 3 {
+		t.Errorf("DecodeUint32() = %v", got)
+	}
+	if err := VertexFormatUnorm1010102.EncodeUint32(buf, []uint32{0, 0, 0, 4}); err == nil {
+		t.Error("EncodeUint32() accepted alpha 4 for a 2-bit component")
+	}
+
+	if err := VertexFormatSint8x2.EncodeInt32(buf, []int32{-128, 127}); err != nil {
+		t.Fatalf("EncodeInt32() error = %v", err)
+	}
+	ints := make([]int32, 2)
+	if err := VertexFormatSint8x2.DecodeInt32(buf, ints); err != nil {
+		t.Fatalf("DecodeInt32() error = %v", err)
+	}
+	if ints[0] != -128 || ints[1] != 127 {
+		t.Errorf("DecodeInt32() = %v", ints)
+	}
+	if err := VertexFormatSint8x2.EncodeInt32(buf, []int32{-129, 0}); err == nil {
+		t.Error("EncodeInt32() accepted -129 for Sint8")
+	}
+
+	if err := VertexFormatFloat32.EncodeUint32(buf, []uint32{1}); !errors.Is(err, ErrVertexFormat) {
+		t.Errorf("EncodeUint32(Float32) error = %v, want %v", err, ErrVertexFormat)
+	}
+	if err := VertexFormatUint32.EncodeInt32(buf, []int32{1}); !errors.Is(err, ErrVertexFormat) {
+		t.Errorf("EncodeInt32(Uint32) error = %v, want %v", err, ErrVertexFormat)
+	}
+}
+
+func TestVertexFormat_EncodeErrors(t *testing.T) {
+	if err := VertexFormatUndefined.EncodeFloat32(make([]byte, 4), nil); !errors.Is(err, ErrVertexFormat) {
+		t.Errorf("Undefined: error = %v, want %v", err, ErrVertexFormat)
+	}
+	if err := VertexFormatFloat32x2.EncodeFloat32(make([]byte, 8), []float32{1}); err == nil {
+		t.Error("wrong component count accepted")
+	}
+	if err := VertexFormatFloat32x2.EncodeFloat32(make([]byte, 4), []float32{1, 2}); err == nil {
+		t.Error("short buffer accepted")
+	}
+	if err := VertexFormatFloat32x2.DecodeFloat32(make([]byte, 8), make([]float32, 1)); err == nil {
+		t.Error("short destination accepted")
+	}
+}
+
+func TestVertexAttributeView(t *testing.T) {
+	layout := VertexBufferLayout{
+		ArrayStride: 12,
+		Attributes: []VertexAttribute{
+			{Format: VertexFormatFloat32x2, Offset: 0, ShaderLocation: 0},
+			{Format: VertexFormatUnorm8x4, Offset: 8, ShaderLocation: 1},
+		},
+	}
+	data := make([]byte, 3*12)
+
+	uv, err := layout.AttributeView(data, 0)
+	if err != nil {
+		t.Fatalf("AttributeView(0) error = %v", err)
+	}
+	color, err := layout.AttributeView(data, 1)
+	if err != nil {
+		t.Fatalf("AttributeView(1) error = %v", err)
+	}
+	if uv.Len() != 3 || color.Len() != 3 {
+		t.Fatalf("Len() = %d, %d, want 3, 3", uv.Len(), color.Len())
+	}
+
+	for i := range 3 {
+		if err := uv.SetFloat32(i, float32(i), float32(-i)); err != nil {
+			t.Fatalf("SetFloat32(%d) error = %v", i, err)
+		}
+		if err := color.SetUint32(i, uint32(i), 0, 0, 255); err != nil {
+			t.Fatalf("SetUint32(%d) error = %v", i, err)
+		}
+	}
+
+	got := make([]float32, 2)
+	if err := uv.Float32(2, got); err != nil || got[0] != 2 || got[1] != -2 {
+		t.Errorf("uv.Float32(2) = %v, %v", got, err)
+	}
+	rgba := make([]float32, 4)
+	if err := color.Float32(1, rgba); err != nil || rgba[0] != 1.0/255 || rgba[3] != 1 {
+		t.Errorf("color.Float32(1) = %v, %v", rgba, err)
+	}
+	if err := uv.SetFloat32(3, 0, 0); err == nil {
+		t.Error("SetFloat32(3) accepted an out-of-range index")
+	}
+	if _, err := layout.AttributeView(data, 7); err == nil {
+		t.Error("AttributeView(7) found a missing location")
+	}
+
+	// The last element only needs room for the attribute, not a full stride.
+	if n := (VertexAttributeView{Data: data[:32], Format: VertexFormatFloat32x2, Stride: 12}).Len(); n != 3 {
+		t.Errorf("Len() with short tail = %d, want 3", n)
+	}
+}
+
+func TestFloat16Conversion(t *testing.T) {
+	tests := []struct {
+		f float32
+		h uint16
+	}{
+		{0, 0x0000},
+		{1, 0x3C00},
+		{-2, 0xC000},
+		{65504, 0x7BFF},
+		{float32(math.Inf(1)), 0x7C00},
+		{5.960464477539063e-8, 0x0001}, // smallest subnormal
+		{6.103515625e-5, 0x0400},       // smallest normal
+		{1.0009765625, 0x3C01},
+		{1.00048828125, 0x3C00}, // halfway, rounds to even
+	}
+	for _, tt := range tests {
+		if got := float32ToHalf(tt.f); got != tt.h {
+			t.Errorf("float32ToHalf(%v) = %#04x, want %#04x", tt.f, got, tt.h)
+		}
+		if got := halfToFloat32(tt.h); tt.f != 1.00048828125 && got != tt.f {
+			t.Errorf("halfToFloat32(%#04x) = %v, want %v", tt.h, got, tt.f)
+		}
+	}
+	if h := float32ToHalf(float32(math.NaN())); h&0x7C00 != 0x7C00 || h&0x3FF == 0 {
+		t.Errorf("float32ToHalf(NaN) = %#04x, want a NaN", h)
+	}
+}
@@ -0,0 +1,221 @@
+package gputypes
+
+import (
+	"fmt"
+	"iter"
+)
+
+// VertexBufferBinding is a vertex buffer bound to a slot for drawing.
+type VertexBufferBinding struct {
+	// Data is the buffer contents.
+	Data []byte
+	// Offset is the byte offset where the bound range starts.
+	Offset uint64
+}
+
+// IndexBufferBinding is an index buffer bound for indexed drawing.
+type IndexBufferBinding struct {
+	// Data is the buffer contents.
+	Data []byte
+	// Format is the index format.
+	Format IndexFormat
+	// Offset is the byte offset where the bound range starts.
+	Offset uint64
+}
+
+// VertexAttributeValue is an attribute value as a vertex shader receives it.
+//
+// Exactly one of Float, Uint or Int is meaningful, as selected by the WGSL
+// type of Format (see VertexFormat.WGSLType). Components the format does
+// not provide are filled with 0, except the fourth which is 1, following
+// WebGPU vertex fetch.
+type VertexAttributeValue struct {
+	// ShaderLocation is the @location the value is delivered to.
+	ShaderLocation uint32
+	// Format is the vertex format the value was fetched from.
+	Format VertexFormat
+	// Float holds the value for f32 shader inputs.
+	Float [4]float32
+	// Uint holds the value for u32 shader inputs.
+	Uint [4]uint32
+	// Int holds the value for i32 shader inputs.
+	Int [4]int32
+}
+
+// FetchedVertex is one vertex shader invocation produced by a VertexPuller.
+type FetchedVertex struct {
+	// Restart is true if this entry is a primitive restart marker instead of
+	// a vertex; no other fields are set in that case.
+	Restart bool
+	// VertexIndex is the @builtin(vertex_index) value.
+	VertexIndex uint32
+	// InstanceIndex is the @builtin(instance_index) value.
+	InstanceIndex uint32
+	// Attributes holds one value per attribute, in buffer and attribute order.
+	Attributes []VertexAttributeValue
+}
+
+// Attribute returns the value fetched for the given shader location.
+func (v FetchedVertex) Attribute(location uint32) (VertexAttributeValue, bool) {
+	for _, a := range v.Attributes {
+		if a.ShaderLocation == location {
+			return a, true
+		}
+	}
+	return VertexAttributeValue{}, false
+}
+
+// VertexPuller is a CPU reference implementation of WebGPU vertex fetch.
+//
+// It decodes vertex attributes the way the vertex input stage does, which
+// makes draw paths testable without a GPU. Out-of-bounds reads, which WebGPU
+// leaves implementation-defined, are reported as errors.
+type VertexPuller struct {
+	// Vertex describes the vertex buffer layouts, as in the render pipeline.
+	Vertex VertexState
+	// Primitive is the primitive state; strip topologies enable primitive restart.
+	Primitive PrimitiveState
+	// VertexBuffers are the bound vertex buffers, indexed by slot.
+	VertexBuffers []VertexBufferBinding
+	// IndexBuffer is the bound index buffer, used by DrawIndexed.
+	IndexBuffer *IndexBufferBinding
+}
+
+// Draw yields the vertices of a non-indexed draw, instance by instance.
+//
+// Iteration stops after the first error, which is yielded with a zero vertex.
+func (p VertexPuller) Draw(vertexCount, instanceCount, firstVertex, firstInstance uint32) iter.Seq2[FetchedVertex, error] {
+	return func(yield func(FetchedVertex, error) bool) {
+		for inst := range instanceCount {
+			for i := range vertexCount {
+				v, err := p.fetch(uint64(firstVertex)+uint64(i), uint64(firstInstance)+uint64(inst))
+				if !yield(v, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// DrawIndexed yields the vertices of an indexed draw, instance by instance.
+//
+// For strip topologies, an index equal to IndexBuffer.Format's primitive
+// restart value yields a FetchedVertex with Restart set. baseVertex is added
+// to every other index before fetching. Iteration stops after the first
+// error, which is yielded with a zero vertex.
+func (p VertexPuller) DrawIndexed(indexCount, instanceCount, firstIndex uint32, baseVertex int32, firstInstance uint32) iter.Seq2[FetchedVertex, error] {
+	return func(yield func(FetchedVertex, error) bool) {
+		ib := p.IndexBuffer
+		if ib == nil {
+			yield(FetchedVertex{}, fmt.Errorf("gputypes: indexed draw without an index buffer"))
+			return
+		}
+		size := uint64(ib.Format.Size())
+		if size == 0 {
+			yield(FetchedVertex{}, fmt.Errorf("gputypes: invalid index format %s", ib.Format))
+			return
+		}
+		strip := p.Primitive.Topology.IsStrip()
+		if strip && p.Primitive.StripIndexFormat != nil && *p.Primitive.StripIndexFormat != ib.Format {
+			yield(FetchedVertex{}, fmt.Errorf("gputypes: strip index format %s does not match index buffer format %s",
+				*p.Primitive.StripIndexFormat, ib.Format))
+			return
+		}
+		end := ib.Offset + (uint64(firstIndex)+uint64(indexCount))*size
+		if end > uint64(len(ib.Data)) {
+			yield(FetchedVertex{}, fmt.Errorf("gputypes: index range [%d, %d) exceeds index buffer of %d bytes",
+				firstIndex, uint64(firstIndex)+uint64(indexCount), len(ib.Data)))
+			return
+		}
+		restart := ib.Format.PrimitiveRestartValue()
+
+		for inst := range instanceCount {
+			for i := range indexCount {
+				at := ib.Offset + (uint64(firstIndex)+uint64(i))*size
+				index := readIndex(ib.Data[at:], ib.Format)
+				if strip && index == restart {
+					if !yield(FetchedVertex{Restart: true}, nil) {
+						return
+					}
+					continue
+				}
+				vertex := int64(index) + int64(baseVertex)
+				if vertex < 0 {
+					yield(FetchedVertex{}, fmt.Errorf("gputypes: index %d with base vertex %d is negative", index, baseVertex))
+					return
+				}
+				v, err := p.fetch(uint64(vertex), uint64(firstInstance)+uint64(inst))
+				if !yield(v, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// fetch decodes every attribute for one vertex and instance.
+func (p VertexPuller) fetch(vertex, instance uint64) (FetchedVertex, error) {
+	out := FetchedVertex{
+		VertexIndex:   uint32(vertex),
+		InstanceIndex: uint32(instance),
+	}
+	for slot, layout := range p.Vertex.Buffers {
+		var element uint64
+		switch layout.StepMode {
+		case VertexStepModeVertexBufferNotUsed:
+			continue
+		case VertexStepModeInstance:
+			element = instance
+		default:
+			element = vertex
+		}
+		if len(layout.Attributes) == 0 {
+			continue
+		}
+		if slot >= len(p.VertexBuffers) {
+			return FetchedVertex{}, fmt.Errorf("gputypes: no vertex buffer bound to slot %d", slot)
+		}
+		buf := p.VertexBuffers[slot]
+		base := buf.Offset + element*layout.ArrayStride
+
+		for _, attr := range layout.Attributes {
+			size := attr.Format.Size()
+			start := base + attr.Offset
+			if start+size > uint64(len(buf.Data)) {
+				return FetchedVertex{}, fmt.Errorf("gputypes: vertex buffer %d: element %d location %d reads [%d, %d) past %d bytes",
+					slot, element, attr.ShaderLocation, start, start+size, len(buf.Data))
+			}
+			value, err := decodeVertexAttribute(buf.Data[start:start+size], attr)
+			if err != nil {
+				return FetchedVertex{}, fmt.Errorf("gputypes: vertex buffer %d location %d: %w", slot, attr.ShaderLocation, err)
+			}
+			out.Attributes = append(out.Attributes, value)
+		}
+	}
+	return out, nil
+}
+
+// decodeVertexAttribute converts raw attribute bytes to the shader-visible value.
+func decodeVertexAttribute(src []byte, attr VertexAttribute) (VertexAttributeValue, error) {
+	v := VertexAttributeValue{
+		ShaderLocation: attr.ShaderLocation,
+		Format:         attr.Format,
+		Float:          [4]float32{0, 0, 0, 1},
+		Uint:           [4]uint32{0, 0, 0, 1},
+		Int:            [4]int32{0, 0, 0, 1},
+	}
+	n := attr.Format.ComponentCount()
+
+	var err error
+	switch {
+	case attr.Format.IsNormalized():
+		err = attr.Format.DecodeFloat32(src, v.Float[:n])
+	case attr.Format.isUnsignedInt():
+		err = attr.Format.DecodeUint32(src, v.Uint[:n])
+	case attr.Format.isSignedInt():
+		err = attr.Format.DecodeInt32(src, v.Int[:n])
+	default:
+		err = attr.Format.DecodeFloat32(src, v.Float[:n])
+	}
+	return v, err
+}
@@ -0,0 +1,152 @@
+package gputypes
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func testPuller(t *testing.T) VertexPuller {
+	t.Helper()
+
+	// Slot 0: per-vertex float32x2 position + uint8x2 id, stride 12.
+	verts := make([]byte, 4*12)
+	for i := range 4 {
+		binary.LittleEndian.PutUint32(verts[i*12:], math.Float32bits(float32(i)))
+		binary.LittleEndian.PutUint32(verts[i*12+4:], math.Float32bits(float32(-i)))
+		verts[i*12+8] = byte(i)
+		verts[i*12+9] = 7
+	}
+	// Slot 1: per-instance sint32 offset, stride 4, bound at byte offset 4.
+	insts := make([]byte, 4+2*4)
+	binary.LittleEndian.PutUint32(insts[4:], uint32(0xFFFFFFFF)) // -1
+	binary.LittleEndian.PutUint32(insts[8:], 5)
+
+	return VertexPuller{
+		Vertex: VertexState{Buffers: []VertexBufferLayout{
+			{ArrayStride: 12, StepMode: VertexStepModeVertex, Attributes: []VertexAttribute{
+				{Format: VertexFormatFloat32x2, Offset: 0, ShaderLocation: 0},
+				{Format: VertexFormatUint8x2, Offset: 8, ShaderLocation: 1},
+			}},
+			{ArrayStride: 4, StepMode: VertexStepModeInstance, Attributes: []VertexAttribute{
+				{Format: VertexFormatSint32, Offset: 0, ShaderLocation: 2},
+			}},
+		}},
+		VertexBuffers: []VertexBufferBinding{{Data: verts}, {Data: insts, Offset: 4}},
+	}
+}
+
+func TestVertexPuller_Draw(t *testing.T) {
+	p := testPuller(t)
+
+	var got []FetchedVertex
+	for v, err := range p.Draw(2, 2, 1, 0) {
+		if err != nil {
+			t.Fatalf("Draw() error = %v", err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 4 {
+		t.Fatalf("Draw() yielded %d vertices, want 4", len(got))
+	}
+
+	// Second instance, second vertex: vertex 2, instance 1.
+	v := got[3]
+	if v.VertexIndex != 2 || v.InstanceIndex != 1 {
+		t.Errorf("indices = (%d, %d), want (2, 1)", v.VertexIndex, v.InstanceIndex)
+	}
+	pos, _ := v.Attribute(0)
+	if pos.Float != [4]float32{2, -2, 0, 1} {
+		t.Errorf("position = %v, want [2 -2 0 1]", pos.Float)
+	}
+	id, _ := v.Attribute(1)
+	if id.Uint != [4]uint32{2, 7, 0, 1} {
+		t.Errorf("id = %v, want [2 7 0 1]", id.Uint)
+	}
+	off, _ := v.Attribute(2)
+	if off.Int[0] != 5 {
+		t.Errorf("instance offset = %d, want 5", off.Int[0])
+	}
+	if off, _ := got[0].Attribute(2); off.Int[0] != -1 {
+		t.Errorf("instance 0 offset = %d, want -1", off.Int[0])
+	}
+}
+
+func TestVertexPuller_DrawIndexedRestart(t *testing.T) {
+	p := testPuller(t)
+	indices := []uint16{0, 1, 0xFFFF, 0, 1}
+	data := make([]byte, 2*len(indices))
+	for i, idx := range indices {
+		binary.LittleEndian.PutUint16(data[2*i:], idx)
+	}
+	p.IndexBuffer = &IndexBufferBinding{Data: data, Format: IndexFormatUint16}
+	p.Primitive.Topology = PrimitiveTopologyTriangleStrip
+
+	var vertices []uint32
+	restarts := 0
+	for v, err := range p.DrawIndexed(5, 1, 0, 2, 0) {
+		if err != nil {
+			t.Fatalf("DrawIndexed() error = %v", err)
+		}
+		if v.Restart {
+			restarts++
+			continue
+		}
+		vertices = append(vertices, v.VertexIndex)
+	}
+	if restarts != 1 {
+		t.Errorf("restarts = %d, want 1", restarts)
+	}
+	want := []uint32{2, 3, 2, 3}
+	if len(vertices) != len(want) {
+		t.Fatalf("vertices = %v, want %v", vertices, want)
+	}
+	for i := range want {
+		if vertices[i] != want[i] {
+			t.Errorf("vertices = %v, want %v", vertices, want)
+			break
+		}
+	}
+
+	// In list topologies the restart value is an ordinary (out-of-range) index.
+	p.Primitive.Topology = PrimitiveTopologyTriangleList
+	var lastErr error
+	for _, err := range p.DrawIndexed(5, 1, 0, 0, 0) {
+		lastErr = err
+	}
+	if lastErr == nil {
+		t.Error("DrawIndexed() with list topology read index 0xFFFF without error")
+	}
+}
+
+func TestVertexPuller_Errors(t *testing.T) {
+	p := testPuller(t)
+	tests := []struct {
+		name string
+		seq  func(yield func(FetchedVertex, error) bool)
+	}{
+		{"vertex out of range", p.Draw(5, 1, 0, 0)},
+		{"instance out of range", p.Draw(1, 3, 0, 0)},
+		{"no index buffer", p.DrawIndexed(3, 1, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lastErr error
+			for _, err := range tt.seq {
+				lastErr = err
+			}
+			if lastErr == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	p.IndexBuffer = &IndexBufferBinding{Data: make([]byte, 4), Format: IndexFormatUint32}
+	u16 := IndexFormatUint16
+	p.Primitive = PrimitiveState{Topology: PrimitiveTopologyLineStrip, StripIndexFormat: &u16}
+	for _, err := range p.DrawIndexed(1, 1, 0, 0, 0) {
+		if err == nil {
+			t.Error("mismatched strip index format accepted")
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package gputypes
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// VertexStream is a float32 attribute stream to be quantized.
+type VertexStream struct {
+	// ShaderLocation is the @location the attribute is bound to.
+	ShaderLocation uint32
+	// Components is the number of components per element (1-4).
+	Components uint32
+	// Data holds the values, Components per element.
+	Data []float32
+	// Tolerance is the maximum absolute error allowed per component after
+	// the shader reconstructs the value, in the units of Data.
+	Tolerance float32
+}
+
+// VertexQuantization describes how an attribute is stored in a compact
+// VertexFormat.
+//
+// The shader reconstructs the original value from the fetched one as
+// value*Scale + Offset, per component. When IsIdentity reports true the
+// fetched value can be used directly.
+type VertexQuantization struct {
+	// Format is the chosen vertex format.
+	Format VertexFormat
+	// Scale is the per-component reconstruction scale.
+	Scale [4]float32
+	// Offset is the per-component reconstruction offset.
+	Offset [4]float32
+	// MaxError is the largest absolute reconstruction error over the stream.
+	MaxError float32
+}
+
+// IsIdentity returns true if no scale or offset needs to be applied.
+func (q VertexQuantization) IsIdentity() bool {
+	return q.Scale == [4]float32{1, 1, 1, 1} && q.Offset == [4]float32{}
+}
+
+// Encode writes one element of components to dst in q.Format, applying the
+// inverse of the reconstruction transform. Missing components are stored as 0.
+func (q VertexQuantization) Encode(dst []byte, components []float32) error {
+	var v [4]float32
+	for i, c := range components {
+		if i >= len(v) {
+			break
+		}
+		v[i] = (c - q.Offset[i]) / q.Scale[i]
+	}
+	return q.Format.EncodeFloat32(dst, v[:q.Format.ComponentCount()])
+}
+
+// quantizationFormats lists the formats RecommendVertexFormat may choose:
+// every normalized and floating-point format, smallest first.
+var quantizationFormats = func() []VertexFormat {
+	var formats []VertexFormat
+	for f := VertexFormatUint8; f <= VertexFormatUnorm8x4BGRA; f++ {
+		switch {
+		case f == VertexFormatUnorm8x4BGRA:
+			// Same precision as Unorm8x4, only the byte order differs.
+		case f.IsNormalized(),
+			f.ComponentType() == VertexComponentTypeFloat16,
+			f.ComponentType() == VertexComponentTypeFloat32:
+			formats = append(formats, f)
+		}
+	}
+	slices.SortStableFunc(formats, func(a, b VertexFormat) int {
+		return cmp.Compare(a.Size(), b.Size())
+	})
+	return formats
+}()
+
+// RecommendVertexFormat returns the smallest VertexFormat that stores data,
+// an attribute stream with the given number of components per element,
+// within tolerance.
+//
+// Candidates are the normalized formats (with a per-component scale and
+// offset mapping the data range onto the normalized range when needed),
+// Float16 and Float32 formats with at least the requested component count;
+// three-component data may use a four-component format or Unorm1010102.
+// Among candidates of the same size, one that needs no scale or offset is
+// preferred, then the one with the smallest error. Float32 formats always
+// qualify, so an error is only returned for invalid input.
+func RecommendVertexFormat(data []float32, components uint32, tolerance float32) (VertexQuantization, error) {
+	if components < 1 || components > 4 {
+		return VertexQuantization{}, fmt.Errorf("gputypes: vertex stream has %d components, want 1-4", components)
+	}
+	if len(data)%int(components) != 0 {
+		return VertexQuantization{}, fmt.Errorf("gputypes: vertex stream length %d is not a multiple of %d components", len(data), components)
+	}
+	if tolerance < 0 || tolerance != tolerance {
+		return VertexQuantization{}, fmt.Errorf("gputypes: invalid vertex quantization tolerance %v", tolerance)
+	}
+
+	lo, hi := [4]float32{}, [4]float32{}
+	for i, v := range data {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return VertexQuantization{}, fmt.Errorf("gputypes: vertex stream value %d is %v", i, v)
+		}
+		c := i % int(components)
+		if i < int(components) || v < lo[c] {
+			lo[c] = v
+		}
+		if i < int(components) || v > hi[c] {
+			hi[c] = v
+		}
+	}
+
+	var best VertexQuantization
+	found, bestIdentity := false, false
+	for _, f := range quantizationFormats {
+		if f.ComponentCount() < components {
+			continue
+		}
+		if found && f.Size() > best.Format.Size() {
+			break
+		}
+		for _, q := range quantizationCandidates(f, components, lo, hi) {
+			q.MaxError = q.maxError(data, components)
+			if q.MaxError > tolerance {
+				continue
+			}
+			identity := q.IsIdentity()
+			if !found || (identity && !bestIdentity) || (identity == bestIdentity && q.MaxError < best.MaxError) {
+				best, found, bestIdentity = q, true, identity
+			}
+		}
+	}
+	return best, nil
+}
+
+// quantizationCandidates returns the transforms worth trying for format f
+// given per-component data bounds.
+func quantizationCandidates(f VertexFormat, components uint32, lo, hi [4]float32) []VertexQuantization {
+	identity := VertexQuantization{Format: f, Scale: [4]float32{1, 1, 1, 1}}
+	if !f.IsNormalized() {
+		return []VertexQuantization{identity}
+	}
+
+	signed := f.isSignedInt()
+	candidates := make([]VertexQuantization, 0, 2)
+	fits := true
+	for c := range components {
+		if hi[c] > 1 || (signed && lo[c] < -1) || (!signed && lo[c] < 0) {
+			fits = false
+		}
+	}
+	if fits {
+		candidates = append(candidates, identity)
+	}
+
+	remapped := identity
+	for c := range components {
+		span := hi[c] - lo[c]
+		switch {
+		case span == 0:
+			remapped.Offset[c] = lo[c]
+		case signed:
+			remapped.Scale[c] = span / 2
+			remapped.Offset[c] = lo[c] + span/2
+		default:
+			remapped.Scale[c] = span
+			remapped.Offset[c] = lo[c]
+		}
+	}
+	if remapped != identity {
+		candidates = append(candidates, remapped)
+	}
+	return candidates
+}
+
+// maxError returns the largest absolute reconstruction error of q over data.
+func (q VertexQuantization) maxError(data []float32, components uint32) float32 {
+	var buf [16]byte
+	var out [4]float32
+	var worst float32
+	for i := 0; i < len(data); i += int(components) {
+		elem := data[i : i+int(components)]
+		if err := q.Encode(buf[:], elem); err != nil {
+			return float32(math.Inf(1))
+		}
+		if err := q.Format.DecodeFloat32(buf[:], out[:]); err != nil {
+			return float32(math.Inf(1))
+		}
+		for c, want := range elem {
+			got := out[c]*q.Scale[c] + q.Offset[c]
+			worst = max(worst, float32(math.Abs(float64(got-want))))
+		}
+	}
+	return worst
+}
+
+// QuantizeVertexStreams chooses a format for every stream with
+// RecommendVertexFormat and packs them into a single interleaved buffer.
+//
+// All streams must have the same element count. Attributes are laid out in
+// stream order, each aligned as its format requires, and the stride is
+// rounded up to a multiple of 4. The returned quantizations are in stream
+// order and describe the scale and offset the shader must apply.
+func QuantizeVertexStreams(streams []VertexStream, stepMode VertexStepMode) (VertexBufferLayout, []byte, []VertexQuantization, error) {
+	layout := VertexBufferLayout{StepMode: stepMode}
+	quants := make([]VertexQuantization, len(streams))
+	count := -1
+
+	for i, s := range streams {
+		q, err := RecommendVertexFormat(s.Data, s.Components, s.Tolerance)
+		if err != nil {
+			return VertexBufferLayout{}, nil, nil, fmt.Errorf("gputypes: vertex stream %d: %w", i, err)
+		}
+		n := len(s.Data) / int(s.Components)
+		if count >= 0 && n != count {
+			return VertexBufferLayout{}, nil, nil, fmt.Errorf("gputypes: vertex stream %d has %d elements, want %d", i, n, count)
+		}
+		count = n
+		quants[i] = q
+
+		offset := alignUp(layout.ArrayStride, q.Format.Alignment())
+		layout.Attributes = append(layout.Attributes, VertexAttribute{
+			Format:         q.Format,
+			Offset:         offset,
+			ShaderLocation: s.ShaderLocation,
+		})
+		layout.ArrayStride = offset + q.Format.Size()
+	}
+	layout.ArrayStride = alignUp(layout.ArrayStride, 4)
+
+	data := make([]byte, max(count, 0)*int(layout.ArrayStride))
+	for i, s := range streams {
+		attr := layout.Attributes[i]
+		c := int(s.Components)
+		for e := range count {
+			dst := data[uint64(e)*layout.ArrayStride+attr.Offset:]
+			if err := quants[i].Encode(dst, s.Data[e*c:(e+1)*c]); err != nil {
+				return VertexBufferLayout{}, nil, nil, fmt.Errorf("gputypes: vertex stream %d: %w", i, err)
+			}
+		}
+	}
+	return layout, data, quants, nil
+}
+
+// alignUp rounds v up to a multiple of align.
+func alignUp(v, align uint64) uint64 {
+	return (v + align - 1) / align * align
+}
@@ -0,0 +1,147 @@
+package gputypes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRecommendVertexFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []float32
+		components uint32
+		tolerance  float32
+		want       VertexFormat
+		identity   bool
+	}{
+		{
+			name:       "uv in unit square",
+			data:       []float32{0, 0, 0.5, 0.25, 1, 1},
+			components: 2, tolerance: 1.0 / 256,
+			want: VertexFormatUnorm8x2, identity: true,
+		},
+		{
+			name:       "uv needs 16 bits",
+			data:       []float32{0, 0, 0.123456, 0.654321, 1, 1},
+			components: 2, tolerance: 1e-4,
+			want: VertexFormatUnorm16x2, identity: true,
+		},
+		{
+			name:       "normals prefer 10 bits",
+			data:       []float32{0, 0, 1, 0.6, 0.8, 0, -0.267, 0.535, 0.802},
+			components: 3, tolerance: 2e-3,
+			want: VertexFormatUnorm1010102, identity: false,
+		},
+		{
+			name:       "coarse normals",
+			data:       []float32{0, 0, 1, 0.6, 0.8, 0, -1, 0, 0},
+			components: 3, tolerance: 1e-2,
+			want: VertexFormatSnorm8x4, identity: true,
+		},
+		{
+			name:       "small positions fit half floats",
+			data:       []float32{-10, 0, 5, 30, 2, -5, 12.5, 1, 0},
+			components: 3, tolerance: 1e-3,
+			want: VertexFormatFloat16x4, identity: true,
+		},
+		{
+			name:       "large positions remapped",
+			data:       []float32{-1000, 0, 500, 1000.3, 2, -5.7, 125.1, 1, 0},
+			components: 3, tolerance: 0.02,
+			want: VertexFormatUnorm16x4, identity: false,
+		},
+		{
+			name:       "exact",
+			data:       []float32{1.0 / 3, 1e7, 12345.678},
+			components: 1, tolerance: 0,
+			want: VertexFormatFloat32, identity: true,
+		},
+		{
+			name:       "constant",
+			data:       []float32{42, 42, 42},
+			components: 1, tolerance: 0,
+			want: VertexFormatUnorm8, identity: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := RecommendVertexFormat(tt.data, tt.components, tt.tolerance)
+			if err != nil {
+				t.Fatalf("RecommendVertexFormat() error = %v", err)
+			}
+			if q.Format != tt.want {
+				t.Errorf("Format = %s, want %s", q.Format, tt.want)
+			}
+			if q.IsIdentity() != tt.identity {
+				t.Errorf("IsIdentity() = %v, want %v (scale %v, offset %v)", q.IsIdentity(), tt.identity, q.Scale, q.Offset)
+			}
+			if q.MaxError > tt.tolerance {
+				t.Errorf("MaxError = %v, exceeds tolerance %v", q.MaxError, tt.tolerance)
+			}
+		})
+	}
+}
+
+func TestRecommendVertexFormat_Errors(t *testing.T) {
+	if _, err := RecommendVertexFormat([]float32{1, 2, 3}, 2, 0.1); err == nil {
+		t.Error("ragged stream accepted")
+	}
+	if _, err := RecommendVertexFormat(nil, 5, 0.1); err == nil {
+		t.Error("5 components accepted")
+	}
+	if _, err := RecommendVertexFormat([]float32{float32(math.NaN())}, 1, 0.1); err == nil {
+		t.Error("NaN accepted")
+	}
+	if _, err := RecommendVertexFormat(nil, 1, -1); err == nil {
+		t.Error("negative tolerance accepted")
+	}
+}
+
+func TestQuantizeVertexStreams(t *testing.T) {
+	positions := []float32{-1, 0, 2, 3, 4, -5}
+	uvs := []float32{0, 0, 1, 1}
+	layout, data, quants, err := QuantizeVertexStreams([]VertexStream{
+		{ShaderLocation: 0, Components: 3, Data: positions, Tolerance: 1e-3},
+		{ShaderLocation: 1, Components: 2, Data: uvs, Tolerance: 1.0 / 256},
+	}, VertexStepModeVertex)
+	if err != nil {
+		t.Fatalf("QuantizeVertexStreams() error = %v", err)
+	}
+
+	if layout.ArrayStride%4 != 0 || uint64(len(data)) != 2*layout.ArrayStride {
+		t.Fatalf("ArrayStride = %d, len(data) = %d", layout.ArrayStride, len(data))
+	}
+	if err := (VertexState{Buffers: []VertexBufferLayout{layout}}).Validate(DefaultLimits()); err != nil {
+		t.Fatalf("produced layout is invalid: %v", err)
+	}
+
+	streams := [][]float32{positions, uvs}
+	for i, attr := range layout.Attributes {
+		view, err := layout.AttributeView(data, attr.ShaderLocation)
+		if err != nil {
+			t.Fatalf("AttributeView(%d) error = %v", attr.ShaderLocation, err)
+		}
+		q := quants[i]
+		c := len(streams[i]) / 2
+		out := make([]float32, 4)
+		for e := range 2 {
+			if err := view.Float32(e, out); err != nil {
+				t.Fatalf("Float32(%d) error = %v", e, err)
+			}
+			for k := range c {
+				got := out[k]*q.Scale[k] + q.Offset[k]
+				want := streams[i][e*c+k]
+				if math.Abs(float64(got-want)) > float64(q.MaxError)+1e-6 {
+					t.Errorf("stream %d element %d component %d = %v, want %v", i, e, k, got, want)
+				}
+			}
+		}
+	}
+
+	if _, _, _, err := QuantizeVertexStreams([]VertexStream{
+		{Components: 1, Data: []float32{1, 2}},
+		{Components: 1, Data: []float32{1}},
+	}, VertexStepModeVertex); err == nil {
+		t.Error("mismatched element counts accepted")
+	}
+}
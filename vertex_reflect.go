@@ -0,0 +1,193 @@
+package gputypes
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// VertexBufferLayoutOf derives a VertexBufferLayout from a Go struct type.
+//
+// v must be a struct value or a pointer to a struct. Fields become vertex
+// attributes when tagged with `gpu:"..."`, a comma-separated list of options:
+//
+//	type Vertex struct {
+//	    Position [3]float32 `gpu:"location=0"`
+//	    Normal   [4]int8    `gpu:"location=1,format=snorm8x4"`
+//	    UV       [2]float32 `gpu:"location=2,format=float32x2"`
+//	    _        [4]byte    // untagged fields are padding
+//	}
+//
+// The location option is required. The format option takes a WebGPU spec
+// name (see VertexFormat.SpecName) and may be omitted when the field type
+// maps unambiguously to a format: float32, uint32, int32, uint16, int16,
+// uint8 and int8 scalars, arrays of 2-4 of them, or structs made only of
+// 2-4 fields of one of them (e.g. a Vec3 with X, Y, Z float32). Normalized
+// and half-float formats must always be spelled out.
+//
+// Offsets come from the Go memory layout (as unsafe.Offsetof reports), and
+// ArrayStride is the size of the struct, so the layout matches a []T
+// uploaded as raw bytes. Untagged embedded structs are flattened; fields
+// tagged `gpu:"-"` are ignored.
+//
+// stepMode must be VertexStepModeVertex or VertexStepModeInstance.
+func VertexBufferLayoutOf(v any, stepMode VertexStepMode) (VertexBufferLayout, error) {
+	if stepMode != VertexStepModeVertex && stepMode != VertexStepModeInstance {
+		return VertexBufferLayout{}, fmt.Errorf("gputypes: vertex step mode must be Vertex or Instance, got %s", stepMode)
+	}
+
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return VertexBufferLayout{}, fmt.Errorf("gputypes: vertex layout source must be a struct, got %v", t)
+	}
+
+	layout := VertexBufferLayout{
+		ArrayStride: uint64(t.Size()),
+		StepMode:    stepMode,
+	}
+	locations := make(map[uint32]string)
+	if err := appendVertexAttributes(&layout, t, 0, "", locations); err != nil {
+		return VertexBufferLayout{}, err
+	}
+	return layout, nil
+}
+
+// appendVertexAttributes walks the fields of t, which starts at byte offset base
+// within the vertex, and appends an attribute for every tagged field.
+func appendVertexAttributes(layout *VertexBufferLayout, t reflect.Type, base uint64, prefix string, locations map[uint32]string) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := prefix + field.Name
+		offset := base + uint64(field.Offset)
+
+		tag, tagged := field.Tag.Lookup("gpu")
+		if tag == "-" {
+			continue
+		}
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := appendVertexAttributes(layout, field.Type, offset, name+".", locations); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		attr, err := parseVertexTag(tag, field.Type)
+		if err != nil {
+			return fmt.Errorf("gputypes: vertex field %s: %w", name, err)
+		}
+		if prev, ok := locations[attr.ShaderLocation]; ok {
+			return fmt.Errorf("gputypes: vertex field %s: location %d already used by %s", name, attr.ShaderLocation, prev)
+		}
+		locations[attr.ShaderLocation] = name
+
+		attr.Offset = offset
+		layout.Attributes = append(layout.Attributes, attr)
+	}
+	return nil
+}
+
+// parseVertexTag parses a `gpu:"..."` tag for a field of type t.
+func parseVertexTag(tag string, t reflect.Type) (VertexAttribute, error) {
+	var attr VertexAttribute
+	hasLocation := false
+
+	for opt := range strings.SplitSeq(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "location":
+			loc, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return attr, fmt.Errorf("invalid location %q", value)
+			}
+			attr.ShaderLocation = uint32(loc)
+			hasLocation = true
+		case "format":
+			attr.Format = vertexFormatFromSpecName(value)
+			if attr.Format == VertexFormatUndefined {
+				return attr, fmt.Errorf("unknown vertex format %q", value)
+			}
+		default:
+			return attr, fmt.Errorf("unknown gpu tag option %q", opt)
+		}
+	}
+
+	if !hasLocation {
+		return attr, fmt.Errorf("missing location in gpu tag %q", tag)
+	}
+	if attr.Format == VertexFormatUndefined {
+		attr.Format = inferVertexFormat(t)
+		if attr.Format == VertexFormatUndefined {
+			return attr, fmt.Errorf("cannot infer vertex format from %v, add format= to the tag", t)
+		}
+	}
+	if attr.Format.Size() > uint64(t.Size()) {
+		return attr, fmt.Errorf("format %s needs %d bytes but %v is %d bytes",
+			attr.Format, attr.Format.Size(), t, t.Size())
+	}
+	return attr, nil
+}
+
+// vertexFormatFromSpecName returns the VertexFormat whose SpecName is name,
+// or VertexFormatUndefined if there is none.
+func vertexFormatFromSpecName(name string) VertexFormat {
+	for f := VertexFormatUint8; f <= VertexFormatUnorm8x4BGRA; f++ {
+		if f.SpecName() == name {
+			return f
+		}
+	}
+	return VertexFormatUndefined
+}
+
+// inferVertexFormat maps a Go type to the single VertexFormat that stores it
+// without conversion, or VertexFormatUndefined if there is no such format.
+func inferVertexFormat(t reflect.Type) VertexFormat {
+	elem, count := t, 1
+	switch t.Kind() {
+	case reflect.Array:
+		elem, count = t.Elem(), t.Len()
+	case reflect.Struct:
+		count = t.NumField()
+		if count == 0 {
+			return VertexFormatUndefined
+		}
+		elem = t.Field(0).Type
+		var offset uintptr
+		for i := range count {
+			f := t.Field(i)
+			if f.Type.Kind() != elem.Kind() || f.Offset != offset {
+				return VertexFormatUndefined
+			}
+			offset += f.Type.Size()
+		}
+	}
+
+	var formats [5]VertexFormat // indexed by component count
+	switch elem.Kind() {
+	case reflect.Float32:
+		formats = [5]VertexFormat{1: VertexFormatFloat32, VertexFormatFloat32x2, VertexFormatFloat32x3, VertexFormatFloat32x4}
+	case reflect.Uint32:
+		formats = [5]VertexFormat{1: VertexFormatUint32, VertexFormatUint32x2, VertexFormatUint32x3, VertexFormatUint32x4}
+	case reflect.Int32:
+		formats = [5]VertexFormat{1: VertexFormatSint32, VertexFormatSint32x2, VertexFormatSint32x3, VertexFormatSint32x4}
+	case reflect.Uint16:
+		formats = [5]VertexFormat{1: VertexFormatUint16, 2: VertexFormatUint16x2, 4: VertexFormatUint16x4}
+	case reflect.Int16:
+		formats = [5]VertexFormat{1: VertexFormatSint16, 2: VertexFormatSint16x2, 4: VertexFormatSint16x4}
+	case reflect.Uint8:
+		formats = [5]VertexFormat{1: VertexFormatUint8, 2: VertexFormatUint8x2, 4: VertexFormatUint8x4}
+	case reflect.Int8:
+		formats = [5]VertexFormat{1: VertexFormatSint8, 2: VertexFormatSint8x2, 4: VertexFormatSint8x4}
+	default:
+		return VertexFormatUndefined
+	}
+	if count < 1 || count > 4 {
+		return VertexFormatUndefined
+	}
+	return formats[count]
+}
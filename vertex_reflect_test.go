@@ -0,0 +1,109 @@
+package gputypes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+type testVec3 struct {
+	X, Y, Z float32
+}
+
+type testVertex struct {
+	Position testVec3   `gpu:"location=0"`
+	Normal   [4]int8    `gpu:"location=1,format=snorm8x4"`
+	UV       [2]float32 `gpu:"location=2"`
+	Color    uint32     `gpu:"location=3,format=unorm8x4"`
+	_        [4]byte
+}
+
+type testInstance struct {
+	testInstanceBase
+	Scale float32 `gpu:"location=6"`
+	Debug string  `gpu:"-"`
+}
+
+type testInstanceBase struct {
+	Offset [3]float32 `gpu:"location=5"`
+}
+
+func TestVertexBufferLayoutOf(t *testing.T) {
+	var v testVertex
+	got, err := VertexBufferLayoutOf(&v, VertexStepModeVertex)
+	if err != nil {
+		t.Fatalf("VertexBufferLayoutOf() error = %v", err)
+	}
+	want := VertexBufferLayout{
+		ArrayStride: uint64(unsafe.Sizeof(v)),
+		StepMode:    VertexStepModeVertex,
+		Attributes: []VertexAttribute{
+			{Format: VertexFormatFloat32x3, Offset: uint64(unsafe.Offsetof(v.Position)), ShaderLocation: 0},
+			{Format: VertexFormatSnorm8x4, Offset: uint64(unsafe.Offsetof(v.Normal)), ShaderLocation: 1},
+			{Format: VertexFormatFloat32x2, Offset: uint64(unsafe.Offsetof(v.UV)), ShaderLocation: 2},
+			{Format: VertexFormatUnorm8x4, Offset: uint64(unsafe.Offsetof(v.Color)), ShaderLocation: 3},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VertexBufferLayoutOf() = %+v, want %+v", got, want)
+	}
+}
+
+func TestVertexBufferLayoutOf_EmbeddedInstance(t *testing.T) {
+	var v testInstance
+	got, err := VertexBufferLayoutOf(v, VertexStepModeInstance)
+	if err != nil {
+		t.Fatalf("VertexBufferLayoutOf() error = %v", err)
+	}
+	want := []VertexAttribute{
+		{Format: VertexFormatFloat32x3, Offset: 0, ShaderLocation: 5},
+		{Format: VertexFormatFloat32, Offset: uint64(unsafe.Offsetof(v.Scale)), ShaderLocation: 6},
+	}
+	if got.StepMode != VertexStepModeInstance {
+		t.Errorf("StepMode = %s, want Instance", got.StepMode)
+	}
+	if !reflect.DeepEqual(got.Attributes, want) {
+		t.Errorf("Attributes = %+v, want %+v", got.Attributes, want)
+	}
+}
+
+func TestVertexBufferLayoutOf_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		v        any
+		stepMode VertexStepMode
+		wantErr  string
+	}{
+		{"not a struct", 42, VertexStepModeVertex, "must be a struct"},
+		{"nil", nil, VertexStepModeVertex, "must be a struct"},
+		{"bad step mode", testVertex{}, VertexStepModeUndefined, "step mode"},
+		{"missing location", struct {
+			A float32 `gpu:"format=float32"`
+		}{}, VertexStepModeVertex, "missing location"},
+		{"duplicate location", struct {
+			A float32 `gpu:"location=0"`
+			B float32 `gpu:"location=0"`
+		}{}, VertexStepModeVertex, "already used by A"},
+		{"ambiguous type", struct {
+			A [3]uint8 `gpu:"location=0"`
+		}{}, VertexStepModeVertex, "cannot infer"},
+		{"unknown format", struct {
+			A float32 `gpu:"location=0,format=float128"`
+		}{}, VertexStepModeVertex, "unknown vertex format"},
+		{"format too large", struct {
+			A float32 `gpu:"location=0,format=float32x4"`
+		}{}, VertexStepModeVertex, "needs 16 bytes"},
+		{"unknown option", struct {
+			A float32 `gpu:"location=0,stride=4"`
+		}{}, VertexStepModeVertex, "unknown gpu tag option"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VertexBufferLayoutOf(tt.v, tt.stepMode)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VertexBufferLayoutOf() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gputypes
+
+import "testing"
+
+// allVertexFormats lists every defined VertexFormat except Undefined.
+var allVertexFormats = []VertexFormat{
+	VertexFormatUint8, VertexFormatUint8x2, VertexFormatUint8x4,
+	VertexFormatSint8, VertexFormatSint8x2, VertexFormatSint8x4,
+	VertexFormatUnorm8, VertexFormatUnorm8x2, VertexFormatUnorm8x4,
+	VertexFormatSnorm8, VertexFormatSnorm8x2, VertexFormatSnorm8x4,
+	VertexFormatUint16, VertexFormatUint16x2, VertexFormatUint16x4,
+	VertexFormatSint16, VertexFormatSint16x2, VertexFormatSint16x4,
+	VertexFormatUnorm16, VertexFormatUnorm16x2, VertexFormatUnorm16x4,
+	VertexFormatSnorm16, VertexFormatSnorm16x2, VertexFormatSnorm16x4,
+	VertexFormatFloat16, VertexFormatFloat16x2, VertexFormatFloat16x4,
+	VertexFormatFloat32, VertexFormatFloat32x2, VertexFormatFloat32x3, VertexFormatFloat32x4,
+	VertexFormatUint32, VertexFormatUint32x2, VertexFormatUint32x3, VertexFormatUint32x4,
+	VertexFormatSint32, VertexFormatSint32x2, VertexFormatSint32x3, VertexFormatSint32x4,
+	VertexFormatUnorm1010102, VertexFormatUnorm8x4BGRA,
+}
+
+func TestVertexFormat_Metadata(t *testing.T) {
+	tests := []struct {
+		format     VertexFormat
+		components uint32
+		component  VertexComponentType
+		normalized bool
+		wgsl       string
+		alignment  uint64
+	}{
+		{VertexFormatUndefined, 0, VertexComponentTypeUndefined, false, "", 0},
+		{VertexFormatUint8x2, 2, VertexComponentTypeUint8, false, "vec2<u32>", 2},
+		{VertexFormatUnorm8x4, 4, VertexComponentTypeUint8, true, "vec4<f32>", 4},
+		{VertexFormatSnorm8x2, 2, VertexComponentTypeSint8, true, "vec2<f32>", 2},
+		{VertexFormatSint16x4, 4, VertexComponentTypeSint16, false, "vec4<i32>", 4},
+		{VertexFormatUnorm16x2, 2, VertexComponentTypeUint16, true, "vec2<f32>", 4},
+		{VertexFormatFloat16x4, 4, VertexComponentTypeFloat16, false, "vec4<f32>", 4},
+		{VertexFormatFloat32, 1, VertexComponentTypeFloat32, false, "f32", 4},
+		{VertexFormatFloat32x3, 3, VertexComponentTypeFloat32, false, "vec3<f32>", 4},
+		{VertexFormatUint32, 1, VertexComponentTypeUint32, false, "u32", 4},
+		{VertexFormatUint32x3, 3, VertexComponentTypeUint32, false, "vec3<u32>", 4},
+		{VertexFormatSint32x4, 4, VertexComponentTypeSint32, false, "vec4<i32>", 4},
+		{VertexFormatUnorm1010102, 4, VertexComponentTypePacked1010102, true, "vec4<f32>", 4},
+		{VertexFormatUint8, 1, VertexComponentTypeUint8, false, "u32", 1},
+		{VertexFormatSnorm8, 1, VertexComponentTypeSint8, true, "f32", 1},
+		{VertexFormatSint16, 1, VertexComponentTypeSint16, false, "i32", 2},
+		{VertexFormatFloat16, 1, VertexComponentTypeFloat16, false, "f32", 2},
+		{VertexFormatUnorm8x4BGRA, 4, VertexComponentTypeUint8, true, "vec4<f32>", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			if got := tt.format.ComponentCount(); got != tt.components {
+				t.Errorf("ComponentCount() = %d, want %d", got, tt.components)
+			}
+			if got := tt.format.ComponentType(); got != tt.component {
+				t.Errorf("ComponentType() = %s, want %s", got, tt.component)
+			}
+			if got := tt.format.IsNormalized(); got != tt.normalized {
+				t.Errorf("IsNormalized() = %v, want %v", got, tt.normalized)
+			}
+			if got := tt.format.WGSLType(); got != tt.wgsl {
+				t.Errorf("WGSLType() = %q, want %q", got, tt.wgsl)
+			}
+			if got := tt.format.Alignment(); got != tt.alignment {
+				t.Errorf("Alignment() = %d, want %d", got, tt.alignment)
+			}
+		})
+	}
+}
+
+// TestVertexFormatCoversAllFormats verifies that every defined VertexFormat
+// is handled by each metadata switch.
+func TestVertexFormatCoversAllFormats(t *testing.T) {
+	for _, f := range allVertexFormats {
+		if f.String() == "Unknown" {
+			t.Errorf("VertexFormat(%#x).String() = Unknown", uint32(f))
+		}
+		if f.Size() == 0 {
+			t.Errorf("VertexFormat(%s).Size() = 0", f)
+		}
+		if f.ComponentCount() == 0 {
+			t.Errorf("VertexFormat(%s).ComponentCount() = 0", f)
+		}
+		if f.ComponentType() == VertexComponentTypeUndefined {
+			t.Errorf("VertexFormat(%s).ComponentType() = Undefined", f)
+		}
+		if f.WGSLType() == "" {
+			t.Errorf("VertexFormat(%s).WGSLType() is empty", f)
+		}
+		if f.SpecName() == "" {
+			t.Errorf("VertexFormat(%s).SpecName() is empty", f)
+		}
+	}
+}
+
+func TestVertexFormat_Values(t *testing.T) {
+	tests := []struct {
+		format VertexFormat
+		want   uint32
+	}{
+		{VertexFormatUint8, 0x01},
+		{VertexFormatUint8x2, 0x02},
+		{VertexFormatUnorm8x4, 0x09},
+		{VertexFormatFloat16, 0x19},
+		{VertexFormatFloat32, 0x1C},
+		{VertexFormatFloat32x4, 0x1F},
+		{VertexFormatSint32x4, 0x27},
+		{VertexFormatUnorm1010102, 0x28},
+		{VertexFormatUnorm8x4BGRA, 0x29},
+	}
+	for _, tt := range tests {
+		if uint32(tt.format) != tt.want {
+			t.Errorf("VertexFormat%s = %#x, want %#x", tt.format, uint32(tt.format), tt.want)
+		}
+	}
+}
+
+func TestVertexFormat_SpecName(t *testing.T) {
+	tests := []struct {
+		format VertexFormat
+		want   string
+	}{
+		{VertexFormatUndefined, ""},
+		{VertexFormatUint8, "uint8"},
+		{VertexFormatUnorm8x4, "unorm8x4"},
+		{VertexFormatFloat16, "float16"},
+		{VertexFormatFloat32x3, "float32x3"},
+		{VertexFormatUnorm1010102, "unorm10-10-10-2"},
+		{VertexFormatUnorm8x4BGRA, "unorm8x4-bgra"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.SpecName(); got != tt.want {
+			t.Errorf("VertexFormat(%s).SpecName() = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gputypes
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Vertex layout validation errors.
+//
+// Validate methods wrap these in a *VertexLayoutError that records where the
+// problem was found; test for them with errors.Is.
+var (
+	// ErrTooManyVertexBuffers means more buffers than Limits.MaxVertexBuffers.
+	ErrTooManyVertexBuffers = errors.New("too many vertex buffers")
+	// ErrVertexStrideAlignment means ArrayStride is not a multiple of 4.
+	ErrVertexStrideAlignment = errors.New("vertex buffer array stride is not a multiple of 4")
+	// ErrVertexStrideTooLarge means ArrayStride exceeds Limits.MaxVertexBufferArrayStride.
+	ErrVertexStrideTooLarge = errors.New("vertex buffer array stride exceeds limit")
+	// ErrVertexStepMode means the step mode is unknown, or attributes are set on an unused buffer.
+	ErrVertexStepMode = errors.New("invalid vertex step mode")
+	// ErrVertexFormat means the attribute format is undefined or unknown.
+	ErrVertexFormat = errors.New("invalid vertex format")
+	// ErrVertexAttributeAlignment means the attribute offset is not a multiple of min(4, format size).
+	ErrVertexAttributeAlignment = errors.New("vertex attribute offset is misaligned")
+	// ErrVertexAttributeOutOfBounds means the attribute does not fit within the array stride.
+	ErrVertexAttributeOutOfBounds = errors.New("vertex attribute exceeds array stride")
+	// ErrVertexLocationTooLarge means the shader location is not below Limits.MaxVertexAttributes.
+	ErrVertexLocationTooLarge = errors.New("vertex shader location exceeds limit")
+	// ErrVertexLocationDuplicate means the shader location is used by more than one attribute.
+	ErrVertexLocationDuplicate = errors.New("duplicate vertex shader location")
+)
+
+// VertexLayoutError reports an invalid vertex buffer layout and where it was found.
+type VertexLayoutError struct {
+	// Buffer is the index into VertexState.Buffers, or -1 if the error
+	// concerns the buffer list as a whole.
+	Buffer int
+	// Attribute is the index into VertexBufferLayout.Attributes, or -1 if
+	// the error concerns the buffer itself.
+	Attribute int
+	// Err describes the problem and wraps one of the ErrVertex* sentinels.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *VertexLayoutError) Error() string {
+	switch {
+	case e.Buffer < 0:
+		return fmt.Sprintf("gputypes: vertex state: %v", e.Err)
+	case e.Attribute < 0:
+		return fmt.Sprintf("gputypes: vertex buffer %d: %v", e.Buffer, e.Err)
+	default:
+		return fmt.Sprintf("gputypes: vertex buffer %d attribute %d: %v", e.Buffer, e.Attribute, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *VertexLayoutError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the vertex buffer layouts against the WebGPU rules and
+// the given limits, returning the first violation as a *VertexLayoutError.
+//
+// It checks that:
+//   - len(Buffers) <= MaxVertexBuffers
+//   - ArrayStride is a multiple of 4 and <= MaxVertexBufferArrayStride
+//   - every attribute has a valid format, an offset aligned to
+//     min(4, format size), and fits within ArrayStride (or within
+//     MaxVertexBufferArrayStride when ArrayStride is 0)
+//   - shader locations are unique across all buffers and below MaxVertexAttributes,
+//     which also bounds the total attribute count
+//
+// A StepMode of VertexStepModeUndefined is treated as VertexStepModeVertex,
+// following webgpu.h.
+func (s VertexState) Validate(limits Limits) error {
+	if len(s.Buffers) > int(limits.MaxVertexBuffers) {
+		return &VertexLayoutError{Buffer: -1, Attribute: -1, Err: fmt.Errorf("%w: %d > %d",
+			ErrTooManyVertexBuffers, len(s.Buffers), limits.MaxVertexBuffers)}
+	}
+
+	locations := make(map[uint32]struct{})
+	for i, buf := range s.Buffers {
+		if err := buf.validate(limits, locations); err != nil {
+			err.Buffer = i
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks a single buffer layout. locations accumulates the shader
+// locations seen so far across buffers. The returned error has Buffer unset.
+func (l VertexBufferLayout) validate(limits Limits, locations map[uint32]struct{}) *VertexLayoutError {
+	bufErr := func(err error) *VertexLayoutError {
+		return &VertexLayoutError{Attribute: -1, Err: err}
+	}
+
+	switch l.StepMode {
+	case VertexStepModeUndefined, VertexStepModeVertex, VertexStepModeInstance:
+	case VertexStepModeVertexBufferNotUsed:
+		if len(l.Attributes) != 0 {
+			return bufErr(fmt.Errorf("%w: unused buffer has %d attributes", ErrVertexStepMode, len(l.Attributes)))
+		}
+	default:
+		return bufErr(fmt.Errorf("%w: %s", ErrVertexStepMode, l.StepMode))
+	}
+
+	if l.ArrayStride%4 != 0 {
+		return bufErr(fmt.Errorf("%w: %d", ErrVertexStrideAlignment, l.ArrayStride))
+	}
+	if l.ArrayStride > uint64(limits.MaxVertexBufferArrayStride) {
+		return bufErr(fmt.Errorf("%w: %d > %d", ErrVertexStrideTooLarge, l.ArrayStride, limits.MaxVertexBufferArrayStride))
+	}
+
+	bound := l.ArrayStride
+	if bound == 0 {
+		bound = uint64(limits.MaxVertexBufferArrayStride)
+	}
+
+	for j, attr := range l.Attributes {
+		attrErr := func(err error) *VertexLayoutError {
+			return &VertexLayoutError{Attribute: j, Err: err}
+		}
+
+		size := attr.Format.Size()
+		if size == 0 {
+			return attrErr(fmt.Errorf("%w: %s", ErrVertexFormat, attr.Format))
+		}
+		if attr.Offset%attr.Format.Alignment() != 0 {
+			return attrErr(fmt.Errorf("%w: offset %d is not a multiple of %d for %s",
+				ErrVertexAttributeAlignment, attr.Offset, attr.Format.Alignment(), attr.Format))
+		}
+		if attr.Offset > bound || size > bound-attr.Offset {
+			return attrErr(fmt.Errorf("%w: offset %d + size %d > %d",
+				ErrVertexAttributeOutOfBounds, attr.Offset, size, bound))
+		}
+		if attr.ShaderLocation >= limits.MaxVertexAttributes {
+			return attrErr(fmt.Errorf("%w: location %d >= %d",
+				ErrVertexLocationTooLarge, attr.ShaderLocation, limits.MaxVertexAttributes))
+		}
+		if _, dup := locations[attr.ShaderLocation]; dup {
+			return attrErr(fmt.Errorf("%w: location %d", ErrVertexLocationDuplicate, attr.ShaderLocation))
+		}
+		locations[attr.ShaderLocation] = struct{}{}
+	}
+	return nil
+}
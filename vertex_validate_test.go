@@ -0,0 +1,109 @@
+package gputypes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVertexState_Validate(t *testing.T) {
+	limits := DefaultLimits()
+	valid := VertexBufferLayout{
+		ArrayStride: 20,
+		StepMode:    VertexStepModeVertex,
+		Attributes: []VertexAttribute{
+			{Format: VertexFormatFloat32x3, Offset: 0, ShaderLocation: 0},
+			{Format: VertexFormatUnorm16x2, Offset: 12, ShaderLocation: 1},
+			{Format: VertexFormatUnorm8, Offset: 19, ShaderLocation: 2},
+		},
+	}
+	one := func(attrs ...VertexAttribute) VertexBufferLayout {
+		return VertexBufferLayout{ArrayStride: 16, StepMode: VertexStepModeInstance, Attributes: attrs}
+	}
+
+	tests := []struct {
+		name      string
+		buffers   []VertexBufferLayout
+		wantErr   error
+		buffer    int
+		attribute int
+	}{
+		{name: "empty", buffers: nil},
+		{name: "valid", buffers: []VertexBufferLayout{valid, one(VertexAttribute{Format: VertexFormatUint32x4, ShaderLocation: 3})}},
+		{name: "zero stride", buffers: []VertexBufferLayout{{Attributes: []VertexAttribute{{Format: VertexFormatFloat32x4, Offset: 2032}}}}},
+		{
+			name:    "too many buffers",
+			buffers: make([]VertexBufferLayout, limits.MaxVertexBuffers+1),
+			wantErr: ErrTooManyVertexBuffers, buffer: -1, attribute: -1,
+		},
+		{
+			name:    "stride alignment",
+			buffers: []VertexBufferLayout{valid, {ArrayStride: 6}},
+			wantErr: ErrVertexStrideAlignment, buffer: 1, attribute: -1,
+		},
+		{
+			name:    "stride too large",
+			buffers: []VertexBufferLayout{{ArrayStride: 2052}},
+			wantErr: ErrVertexStrideTooLarge, buffer: 0, attribute: -1,
+		},
+		{
+			name:    "unused buffer with attributes",
+			buffers: []VertexBufferLayout{{StepMode: VertexStepModeVertexBufferNotUsed, Attributes: valid.Attributes}},
+			wantErr: ErrVertexStepMode, buffer: 0, attribute: -1,
+		},
+		{
+			name:    "undefined format",
+			buffers: []VertexBufferLayout{one(VertexAttribute{})},
+			wantErr: ErrVertexFormat, buffer: 0, attribute: 0,
+		},
+		{
+			name:    "misaligned offset",
+			buffers: []VertexBufferLayout{one(VertexAttribute{Format: VertexFormatFloat16, Offset: 3})},
+			wantErr: ErrVertexAttributeAlignment, buffer: 0, attribute: 0,
+		},
+		{
+			name: "out of bounds",
+			buffers: []VertexBufferLayout{one(
+				VertexAttribute{Format: VertexFormatFloat32, ShaderLocation: 0},
+				VertexAttribute{Format: VertexFormatFloat32x2, Offset: 12, ShaderLocation: 1},
+			)},
+			wantErr: ErrVertexAttributeOutOfBounds, buffer: 0, attribute: 1,
+		},
+		{
+			name:    "out of bounds with zero stride",
+			buffers: []VertexBufferLayout{{Attributes: []VertexAttribute{{Format: VertexFormatFloat32x4, Offset: 2036}}}},
+			wantErr: ErrVertexAttributeOutOfBounds, buffer: 0, attribute: 0,
+		},
+		{
+			name:    "location too large",
+			buffers: []VertexBufferLayout{one(VertexAttribute{Format: VertexFormatFloat32, ShaderLocation: 16})},
+			wantErr: ErrVertexLocationTooLarge, buffer: 0, attribute: 0,
+		},
+		{
+			name:    "duplicate location across buffers",
+			buffers: []VertexBufferLayout{valid, one(VertexAttribute{Format: VertexFormatFloat32, ShaderLocation: 1})},
+			wantErr: ErrVertexLocationDuplicate, buffer: 1, attribute: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VertexState{Buffers: tt.buffers}.Validate(limits)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			var layoutErr *VertexLayoutError
+			if !errors.As(err, &layoutErr) {
+				t.Fatalf("Validate() error = %T, want *VertexLayoutError", err)
+			}
+			if layoutErr.Buffer != tt.buffer || layoutErr.Attribute != tt.attribute {
+				t.Errorf("error at buffer %d attribute %d, want buffer %d attribute %d",
+					layoutErr.Buffer, layoutErr.Attribute, tt.buffer, tt.attribute)
+			}
+		})
+	}
+}
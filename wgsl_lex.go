@@ -0,0 +1,143 @@
+package gputypes
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wgslTokenKind classifies a WGSL token.
+type wgslTokenKind uint8
+
+const (
+	wgslEOF wgslTokenKind = iota
+	wgslIdent
+	wgslNumber
+	wgslPunct
+)
+
+// wgslToken is a WGSL token with its source position.
+type wgslToken struct {
+	kind wgslTokenKind
+	text string
+	// off is the byte offset of the token in the source.
+	off int
+	// line and col are 1-based; col counts bytes.
+	line, col int
+}
+
+// lexWGSL splits WGSL source into identifiers, numeric literals and
+// punctuation, skipping whitespace and comments. The result always ends
+// with a wgslEOF token.
+//
+// Punctuation is one token per character except "->", so template lists
+// such as array<vec4<f32>> never produce ">>".
+func lexWGSL(src string) ([]wgslToken, error) {
+	var toks []wgslToken
+	line, lineStart := 1, 0
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			i++
+			line, lineStart = line+1, i
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\v' || c == '\f':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			start := wgslToken{off: i, line: line, col: i - lineStart + 1}
+			depth := 0
+			for i < len(src) {
+				switch {
+				case strings.HasPrefix(src[i:], "/*"):
+					depth++
+					i += 2
+				case strings.HasPrefix(src[i:], "*/"):
+					depth--
+					i += 2
+				case src[i] == '\n':
+					i++
+					line, lineStart = line+1, i
+				default:
+					i++
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if depth != 0 {
+				return nil, wgslErrorf(start, ErrWGSLParse, "unterminated block comment")
+			}
+			continue
+		}
+
+		tok := wgslToken{off: i, line: line, col: i - lineStart + 1}
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			tok.kind = wgslIdent
+			j := i + size
+			for j < len(src) {
+				r, size := utf8.DecodeRuneInString(src[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			tok.text = src[i:j]
+		case isDigit(c) || c == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			tok.kind = wgslNumber
+			tok.text = src[i:lexWGSLNumber(src, i)]
+		case r == utf8.RuneError && size == 1:
+			return nil, wgslErrorf(tok, ErrWGSLParse, "invalid UTF-8")
+		case strings.HasPrefix(src[i:], "->"):
+			tok.kind = wgslPunct
+			tok.text = "->"
+		case strings.ContainsRune("@(){}[]<>,;:.=+-*/%&|^!~?", r):
+			tok.kind = wgslPunct
+			tok.text = src[i : i+1]
+		default:
+			return nil, wgslErrorf(tok, ErrWGSLParse, "unexpected character %q", r)
+		}
+		toks = append(toks, tok)
+		i += len(tok.text)
+	}
+	return append(toks, wgslToken{kind: wgslEOF, off: i, line: line, col: i - lineStart + 1}), nil
+}
+
+// lexWGSLNumber returns the end of the numeric literal starting at i. It
+// accepts decimal and hexadecimal integers and floats with their suffixes;
+// a sign is part of the literal only directly after an exponent marker.
+func lexWGSLNumber(src string, i int) int {
+	hex := strings.HasPrefix(src[i:], "0x") || strings.HasPrefix(src[i:], "0X")
+	j := i
+	for j < len(src) {
+		c := src[j]
+		switch {
+		case isDigit(c) || c == '.' || c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			j++
+		case (c == '+' || c == '-') && j > i:
+			p := src[j-1]
+			if hex && (p == 'p' || p == 'P') || !hex && (p == 'e' || p == 'E') {
+				j++
+				continue
+			}
+			return j
+		default:
+			return j
+		}
+	}
+	return j
+}
+
+// isDigit reports whether c is an ASCII decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,45 @@
+package gputypes
+
+import "testing"
+
+func TestLexWGSL(t *testing.T) {
+	src := "fn f()->array<vec4<f32>>{ // c\n  x=0x1p-3+1e+5-.5f; /* a /* b */ */ é_1 }"
+	want := []wgslToken{
+		{wgslIdent, "fn", 0, 1, 1},
+		{wgslIdent, "f", 3, 1, 4},
+		{wgslPunct, "(", 4, 1, 5},
+		{wgslPunct, ")", 5, 1, 6},
+		{wgslPunct, "->", 6, 1, 7},
+		{wgslIdent, "array", 8, 1, 9},
+		{wgslPunct, "<", 13, 1, 14},
+		{wgslIdent, "vec4", 14, 1, 15},
+		{wgslPunct, "<", 18, 1, 19},
+		{wgslIdent, "f32", 19, 1, 20},
+		{wgslPunct, ">", 22, 1, 23},
+		{wgslPunct, ">", 23, 1, 24},
+		{wgslPunct, "{", 24, 1, 25},
+		{wgslIdent, "x", 33, 2, 3},
+		{wgslPunct, "=", 34, 2, 4},
+		{wgslNumber, "0x1p-3", 35, 2, 5},
+		{wgslPunct, "+", 41, 2, 11},
+		{wgslNumber, "1e+5", 42, 2, 12},
+		{wgslPunct, "-", 46, 2, 16},
+		{wgslNumber, ".5f", 47, 2, 17},
+		{wgslPunct, ";", 50, 2, 20},
+		{wgslIdent, "é_1", 68, 2, 38},
+		{wgslPunct, "}", 73, 2, 43},
+		{wgslEOF, "", 74, 2, 44},
+	}
+	got, err := lexWGSL(src)
+	if err != nil {
+		t.Fatalf("lexWGSL() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("lexWGSL() = %d tokens %v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("token %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
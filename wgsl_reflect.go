@@ -0,0 +1,1528 @@
+package gputypes
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WGSL reflection errors.
+//
+// ReflectWGSL wraps these in a *WGSLError that records the source
+// position; test for them with errors.Is.
+var (
+	// ErrWGSLParse means the source is not valid WGSL.
+	ErrWGSLParse = errors.New("invalid WGSL")
+	// ErrWGSLUnsupported means the source uses a construct that cannot be
+	// reflected, such as texture_external or a binding array without a count.
+	ErrWGSLUnsupported = errors.New("unsupported WGSL construct")
+)
+
+// WGSLError reports a problem at a position in WGSL source.
+type WGSLError struct {
+	// Line is the 1-based line number.
+	Line int
+	// Column is the 1-based column, in bytes.
+	Column int
+	// Err describes the problem and wraps ErrWGSLParse or ErrWGSLUnsupported.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *WGSLError) Error() string {
+	return fmt.Sprintf("gputypes: wgsl:%d:%d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *WGSLError) Unwrap() error {
+	return e.Err
+}
+
+// wgslErrorf returns a *WGSLError at tok wrapping sentinel.
+func wgslErrorf(tok wgslToken, sentinel error, format string, args ...any) error {
+	return &WGSLError{Line: tok.line, Column: tok.col, Err: fmt.Errorf("%w: "+format, append([]any{sentinel}, args...)...)}
+}
+
+// ShaderReflection is the interface of a WGSL module, as reported by
+// ReflectWGSL.
+type ShaderReflection struct {
+	// EntryPoints are the @vertex, @fragment and @compute functions, in
+	// source order.
+	EntryPoints []ShaderEntryPoint
+	// Bindings are the @group/@binding resources, sorted by group and binding.
+	Bindings []ShaderBinding
+	// Overrides are the override declarations, in source order.
+	Overrides []ShaderOverride
+}
+
+// ShaderEntryPoint describes an entry point function.
+type ShaderEntryPoint struct {
+	// Name is the function name.
+	Name string
+	// Stage is the shader stage.
+	Stage ShaderStage
+	// WorkgroupSize is the @workgroup_size of a compute entry point. A
+	// component set by an override takes the override's default, or 0.
+	WorkgroupSize WorkgroupSize
+	// WorkgroupSizeOverrides names the override behind each WorkgroupSize
+	// component, or is empty for constant components.
+	WorkgroupSizeOverrides [3]string
+	// Inputs are the @location parameters, including members of struct
+	// parameters, sorted by location. For vertex entry points these are the
+	// vertex attributes.
+	Inputs []ShaderIO
+	// Outputs are the @location results, including members of a returned
+	// struct, sorted by location. For fragment entry points these are the
+	// color targets.
+	Outputs []ShaderIO
+}
+
+// ShaderIO is a user-defined entry point input or output.
+type ShaderIO struct {
+	// Name is the parameter or struct member name; empty for a return value.
+	Name string
+	// Location is the @location value.
+	Location uint32
+	// Type is the WGSL type with aliases expanded, e.g. "vec4<f32>".
+	Type string
+	// Format is the vertex format that matches Type without conversion
+	// (Float32x4 for vec4<f32>, Sint32x2 for vec2<i32>, Float16x4 for
+	// vec4<f16>), or Undefined if there is none.
+	Format VertexFormat
+}
+
+// ShaderBinding is a resource variable declared with @group and @binding.
+type ShaderBinding struct {
+	// Name is the variable name.
+	Name string
+	// Group is the @group value.
+	Group uint32
+	// Type is the WGSL store type with aliases expanded.
+	Type string
+	// Entry is the layout entry for the resource. Its Visibility holds the
+	// stages of the entry points that use it.
+	Entry BindGroupLayoutEntry
+	// EntryPoints names the entry points that use the resource.
+	EntryPoints []string
+	// SampledBy names the entry points that pass the resource to a
+	// sampling builtin such as textureSample, directly or through function
+	// parameters.
+	SampledBy []string
+}
+
+// sampleType returns Entry.Texture with the sample type of a non-multisampled
+// f32 texture set for a pipeline of the given entry points: Float if one of
+// them samples the texture, UnfilterableFloat otherwise. Other entries are
+// returned unchanged.
+func (b *ShaderBinding) sampleType(entryPoints []string) *TextureBindingLayout {
+	tex := b.Entry.Texture
+	if tex == nil || tex.Multisampled ||
+		tex.SampleType != TextureSampleTypeFloat && tex.SampleType != TextureSampleTypeUnfilterableFloat {
+		return tex
+	}
+	out := *tex
+	out.SampleType = TextureSampleTypeUnfilterableFloat
+	if slices.ContainsFunc(b.SampledBy, func(name string) bool { return slices.Contains(entryPoints, name) }) {
+		out.SampleType = TextureSampleTypeFloat
+	}
+	return &out
+}
+
+// ShaderOverride is a pipeline-overridable constant.
+type ShaderOverride struct {
+	// Name is the override name.
+	Name string
+	// ID is the @id value, valid if HasID is set.
+	ID uint32
+	// HasID reports whether the override has an @id attribute.
+	HasID bool
+	// Type is the declared type, or the type of a literal default when the
+	// type is omitted; empty if it cannot be inferred.
+	Type string
+	// Default is the source text of the initializer; empty if there is none.
+	Default string
+}
+
+// ReflectWGSL parses WGSL source and reports its entry points, resource
+// bindings and overrides, and returns the first problem as a *WGSLError.
+//
+// Resources map to BindGroupLayoutEntry the way the WebGPU "auto" layout
+// does:
+//   - var<uniform> is a Uniform buffer and var<storage> a ReadOnlyStorage
+//     or, with read_write access, Storage buffer; MinBindingSize is the
+//     size of the store type, counting one element of a runtime-sized array
+//   - sampler is Filtering and sampler_comparison is Comparison
+//   - sampled textures use the sample type of their texel type and depth
+//     textures use Depth; f32 is Float when an entry point passes the
+//     texture to textureSample, textureSampleBias, textureSampleLevel,
+//     textureSampleGrad, textureSampleBaseClampToEdge or textureGather,
+//     directly or through function parameters, and UnfilterableFloat
+//     otherwise or when multisampled
+//   - storage textures use their texel format and access
+//   - binding_array<T, N> sets Count to N
+//
+// A resource is used by an entry point when its name appears in the entry
+// point or in a function it calls, directly or indirectly. Local names that
+// shadow a resource are not told apart.
+//
+// Only declarations are parsed; function bodies are skipped, so ReflectWGSL
+// does not detect every invalid module.
+func ReflectWGSL(source ShaderSourceWGSL) (*ShaderReflection, error) {
+	toks, err := lexWGSL(source.Code)
+	if err != nil {
+		return nil, err
+	}
+	p := &wgslParser{
+		src:           source.Code,
+		c:             wgslCursor{toks: toks},
+		structs:       make(map[string]*wgslStruct),
+		aliases:       make(map[string]wgslType),
+		consts:        make(map[string][]wgslToken),
+		overrides:     make(map[string]*ShaderOverride),
+		overrideInits: make(map[string][]wgslToken),
+		overrideIDs:   make(map[string][]wgslToken),
+		funcs:         make(map[string]*wgslFunc),
+	}
+	if err := p.parseModule(); err != nil {
+		return nil, err
+	}
+	return p.reflect()
+}
+
+// EntryPoint returns the entry point with the given name.
+func (r *ShaderReflection) EntryPoint(name string) (*ShaderEntryPoint, bool) {
+	for i := range r.EntryPoints {
+		if r.EntryPoints[i].Name == name {
+			return &r.EntryPoints[i], true
+		}
+	}
+	return nil, false
+}
+
+// BindGroupLayouts builds the bind group layouts of a pipeline using the
+// named entry points, or every entry point if none are named, as the
+// WebGPU "auto" layout does.
+//
+// Only resources used by those entry points are included, with Visibility
+// set to the stages that use them. An f32 texture is Float only if one of
+// those entry points samples it (see ShaderBinding.SampledBy). The result has one descriptor per group
+// up to the highest group used; groups without resources are empty. If two
+// resources share a group and binding, the first is kept.
+func (r *ShaderReflection) BindGroupLayouts(entryPoints ...string) []BindGroupLayoutDescriptor {
+	stages := make(map[string]ShaderStage, len(r.EntryPoints))
+	for _, ep := range r.EntryPoints {
+		if len(entryPoints) == 0 || slices.Contains(entryPoints, ep.Name) {
+			stages[ep.Name] = ep.Stage
+		}
+	}
+
+	var groups []BindGroupLayoutDescriptor
+	for _, b := range r.Bindings {
+		var visibility ShaderStages
+		for _, name := range b.EntryPoints {
+			visibility |= stages[name]
+		}
+		if visibility == ShaderStageNone {
+			continue
+		}
+		for uint32(len(groups)) <= b.Group {
+			groups = append(groups, BindGroupLayoutDescriptor{})
+		}
+		g := &groups[b.Group]
+		if slices.ContainsFunc(g.Entries, func(e BindGroupLayoutEntry) bool { return e.Binding == b.Entry.Binding }) {
+			continue
+		}
+		entry := b.Entry
+		entry.Visibility = visibility
+		entry.Texture = b.sampleType(slices.Collect(maps.Keys(stages)))
+		g.Entries = append(g.Entries, entry)
+	}
+	return groups
+}
+
+// wgslCursor walks a token slice. Past the end it returns an EOF token
+// positioned after the last token.
+type wgslCursor struct {
+	toks []wgslToken
+	pos  int
+}
+
+// peek returns the current token.
+func (c *wgslCursor) peek() wgslToken {
+	if c.pos < len(c.toks) {
+		return c.toks[c.pos]
+	}
+	var eof wgslToken
+	if n := len(c.toks); n > 0 {
+		last := c.toks[n-1]
+		eof = wgslToken{off: last.off + len(last.text), line: last.line, col: last.col + len(last.text)}
+	}
+	return eof
+}
+
+// next returns the current token and advances.
+func (c *wgslCursor) next() wgslToken {
+	t := c.peek()
+	if c.pos < len(c.toks) {
+		c.pos++
+	}
+	return t
+}
+
+// eof reports whether all tokens are consumed.
+func (c *wgslCursor) eof() bool {
+	return c.peek().kind == wgslEOF
+}
+
+// is reports whether the current token is the identifier or punctuation text.
+func (c *wgslCursor) is(text string) bool {
+	t := c.peek()
+	return t.kind != wgslNumber && t.kind != wgslEOF && t.text == text
+}
+
+// accept consumes the current token if it is text.
+func (c *wgslCursor) accept(text string) bool {
+	if c.is(text) {
+		c.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes text or reports an error.
+func (c *wgslCursor) expect(text string) (wgslToken, error) {
+	t := c.peek()
+	if !c.accept(text) {
+		return t, wgslErrorf(t, ErrWGSLParse, "expected %q, found %s", text, t.describe())
+	}
+	return t, nil
+}
+
+// ident consumes an identifier or reports an error.
+func (c *wgslCursor) ident() (wgslToken, error) {
+	t := c.peek()
+	if t.kind != wgslIdent {
+		return t, wgslErrorf(t, ErrWGSLParse, "expected identifier, found %s", t.describe())
+	}
+	c.pos++
+	return t, nil
+}
+
+// describe names the token for error messages.
+func (t wgslToken) describe() string {
+	if t.kind == wgslEOF {
+		return "end of input"
+	}
+	return strconv.Quote(t.text)
+}
+
+// list consumes a parenthesized or angle-bracketed list opened by the
+// current token and returns its elements split at top-level commas. A
+// trailing comma is allowed.
+func (c *wgslCursor) list(open, close string) ([][]wgslToken, error) {
+	start, err := c.expect(open)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		items [][]wgslToken
+		item  []wgslToken
+		depth int
+	)
+	// Inside a parenthesized list "<" and ">" are comparison operators,
+	// so only template lists nest on them.
+	angles := open == "<"
+	for {
+		t := c.next()
+		switch {
+		case t.kind == wgslEOF:
+			return nil, wgslErrorf(start, ErrWGSLParse, "unclosed %q", open)
+		case t.kind == wgslPunct && (t.text == "(" || t.text == "[" || angles && t.text == "<"):
+			depth++
+		case t.kind == wgslPunct && depth == 0 && t.text == close:
+			if len(item) > 0 {
+				items = append(items, item)
+			}
+			return items, nil
+		case t.kind == wgslPunct && depth > 0 && (t.text == ")" || t.text == "]" || angles && t.text == ">"):
+			depth--
+		case t.kind == wgslPunct && depth == 0 && t.text == ",":
+			if len(item) == 0 {
+				return nil, wgslErrorf(t, ErrWGSLParse, "empty list element")
+			}
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append(item, t)
+	}
+}
+
+// skipPast consumes tokens up to and including the next top-level text.
+func (c *wgslCursor) skipPast(text string) error {
+	depth := 0
+	for {
+		t := c.next()
+		switch {
+		case t.kind == wgslEOF:
+			return wgslErrorf(t, ErrWGSLParse, "expected %q, found end of input", text)
+		case t.kind == wgslPunct && depth == 0 && t.text == text:
+			return nil
+		case t.kind == wgslPunct && (t.text == "(" || t.text == "[" || t.text == "{"):
+			depth++
+		case t.kind == wgslPunct && (t.text == ")" || t.text == "]" || t.text == "}"):
+			depth--
+		}
+	}
+}
+
+// wgslAttr is an attribute such as @location(0).
+type wgslAttr struct {
+	tok  wgslToken
+	name string
+	args [][]wgslToken
+}
+
+// wgslType is a type specifier; template arguments are kept as tokens
+// because they may be types or expressions.
+type wgslType struct {
+	tok  wgslToken
+	name string
+	args [][]wgslToken
+}
+
+// wgslMember is a struct member or function parameter.
+type wgslMember struct {
+	attrs []wgslAttr
+	name  wgslToken
+	typ   wgslType
+}
+
+// wgslStruct is a struct declaration.
+type wgslStruct struct {
+	members []wgslMember
+}
+
+// wgslFunc is a function declaration.
+type wgslFunc struct {
+	name        wgslToken
+	attrs       []wgslAttr
+	params      []wgslMember
+	result      *wgslType
+	resultAttrs []wgslAttr
+	// refs are the identifiers in the function body.
+	refs map[string]bool
+	// sampled are the identifiers passed to the builtins in
+	// wgslSampleBuiltins.
+	sampled map[string]bool
+	// calls are the calls in the function body, to user functions or not.
+	calls []wgslCall
+}
+
+// wgslCall is a call in a function body.
+type wgslCall struct {
+	callee string
+	// args are the identifiers in each argument.
+	args [][]string
+}
+
+// wgslVar is a module-scope variable with @group and @binding.
+type wgslVar struct {
+	name          wgslToken
+	group         []wgslToken
+	binding       []wgslToken
+	addressSpace  string
+	access        string
+	typ           wgslType
+	hasType       bool
+	addressTokens []wgslToken
+}
+
+// wgslParser holds the module-scope declarations of a WGSL module.
+type wgslParser struct {
+	src       string
+	c         wgslCursor
+	structs   map[string]*wgslStruct
+	aliases   map[string]wgslType
+	consts    map[string][]wgslToken
+	overrides map[string]*ShaderOverride
+	// overrideInits and overrideIDs are the initializer and @id tokens of
+	// the overrides, evaluated once every const is known.
+	overrideInits map[string][]wgslToken
+	overrideIDs   map[string][]wgslToken
+	overList      []*ShaderOverride
+	funcs         map[string]*wgslFunc
+	entries       []*wgslFunc
+	vars          []*wgslVar
+	evalDepth     int
+}
+
+// parseModule parses every module-scope declaration.
+func (p *wgslParser) parseModule() error {
+	c := &p.c
+	for !c.eof() {
+		attrs, err := p.parseAttrs(c)
+		if err != nil {
+			return err
+		}
+		t := c.peek()
+		switch {
+		case c.accept(";"):
+		case c.is("enable"), c.is("requires"), c.is("diagnostic"), c.is("const_assert"):
+			err = c.skipPast(";")
+		case c.accept("alias"):
+			err = p.parseAlias()
+		case c.accept("struct"):
+			err = p.parseStruct()
+		case c.accept("fn"):
+			err = p.parseFunc(attrs)
+		case c.accept("var"):
+			err = p.parseVar(t, attrs)
+		case c.accept("const"):
+			err = p.parseConst()
+		case c.accept("override"):
+			err = p.parseOverride(attrs)
+		default:
+			err = wgslErrorf(t, ErrWGSLParse, "unexpected %s at module scope", t.describe())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAttrs parses a possibly empty attribute list.
+func (p *wgslParser) parseAttrs(c *wgslCursor) ([]wgslAttr, error) {
+	var attrs []wgslAttr
+	for c.is("@") {
+		at := c.next()
+		name, err := c.ident()
+		if err != nil {
+			return nil, err
+		}
+		a := wgslAttr{tok: at, name: name.text}
+		if c.is("(") {
+			if a.args, err = c.list("(", ")"); err != nil {
+				return nil, err
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs, nil
+}
+
+// findAttr returns the named attribute, or nil.
+func findAttr(attrs []wgslAttr, name string) *wgslAttr {
+	for i := range attrs {
+		if attrs[i].name == name {
+			return &attrs[i]
+		}
+	}
+	return nil
+}
+
+// parseType parses a type specifier at the cursor.
+func (p *wgslParser) parseType(c *wgslCursor) (wgslType, error) {
+	name, err := c.ident()
+	if err != nil {
+		return wgslType{}, err
+	}
+	t := wgslType{tok: name, name: name.text}
+	if c.is("<") {
+		if t.args, err = c.list("<", ">"); err != nil {
+			return wgslType{}, err
+		}
+	}
+	return t, nil
+}
+
+// typeArg parses template argument i of t as a type.
+func (p *wgslParser) typeArg(t wgslType, i int) (wgslType, error) {
+	if i >= len(t.args) {
+		return wgslType{}, wgslErrorf(t.tok, ErrWGSLParse, "%s needs %d template arguments", t.name, i+1)
+	}
+	c := wgslCursor{toks: t.args[i]}
+	at, err := p.parseType(&c)
+	if err == nil && !c.eof() {
+		err = wgslErrorf(c.peek(), ErrWGSLParse, "unexpected %s in type", c.peek().describe())
+	}
+	return at, err
+}
+
+// identArg returns template argument i of t, which must be an identifier.
+func (p *wgslParser) identArg(t wgslType, i int) (wgslToken, error) {
+	if i >= len(t.args) {
+		return wgslToken{}, wgslErrorf(t.tok, ErrWGSLParse, "%s needs %d template arguments", t.name, i+1)
+	}
+	if arg := t.args[i]; len(arg) != 1 || arg[0].kind != wgslIdent {
+		return arg[0], wgslErrorf(arg[0], ErrWGSLParse, "expected identifier in %s", t.name)
+	}
+	return t.args[i][0], nil
+}
+
+// parseAlias parses "alias Name = type;".
+func (p *wgslParser) parseAlias() error {
+	c := &p.c
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	if _, err := c.expect("="); err != nil {
+		return err
+	}
+	t, err := p.parseType(c)
+	if err != nil {
+		return err
+	}
+	p.aliases[name.text] = t
+	_, err = c.expect(";")
+	return err
+}
+
+// parseStruct parses "struct Name { members }".
+func (p *wgslParser) parseStruct() error {
+	c := &p.c
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	if _, err := c.expect("{"); err != nil {
+		return err
+	}
+	s := &wgslStruct{}
+	for !c.accept("}") {
+		m, err := p.parseMember(c)
+		if err != nil {
+			return err
+		}
+		s.members = append(s.members, m)
+		if !c.accept(",") && !c.is("}") {
+			return wgslErrorf(c.peek(), ErrWGSLParse, "expected \",\" or \"}\", found %s", c.peek().describe())
+		}
+	}
+	p.structs[name.text] = s
+	return nil
+}
+
+// parseMember parses "attrs name : type".
+func (p *wgslParser) parseMember(c *wgslCursor) (wgslMember, error) {
+	attrs, err := p.parseAttrs(c)
+	if err != nil {
+		return wgslMember{}, err
+	}
+	name, err := c.ident()
+	if err != nil {
+		return wgslMember{}, err
+	}
+	if _, err := c.expect(":"); err != nil {
+		return wgslMember{}, err
+	}
+	t, err := p.parseType(c)
+	return wgslMember{attrs: attrs, name: name, typ: t}, err
+}
+
+// parseFunc parses a function declaration, recording the identifiers its
+// body refers to.
+func (p *wgslParser) parseFunc(attrs []wgslAttr) error {
+	c := &p.c
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	f := &wgslFunc{name: name, attrs: attrs, refs: make(map[string]bool), sampled: make(map[string]bool)}
+	if _, err := c.expect("("); err != nil {
+		return err
+	}
+	for !c.accept(")") {
+		m, err := p.parseMember(c)
+		if err != nil {
+			return err
+		}
+		f.params = append(f.params, m)
+		if !c.accept(",") && !c.is(")") {
+			return wgslErrorf(c.peek(), ErrWGSLParse, "expected \",\" or \")\", found %s", c.peek().describe())
+		}
+	}
+	if c.accept("->") {
+		if f.resultAttrs, err = p.parseAttrs(c); err != nil {
+			return err
+		}
+		t, err := p.parseType(c)
+		if err != nil {
+			return err
+		}
+		f.result = &t
+	}
+
+	open, err := c.expect("{")
+	if err != nil {
+		return err
+	}
+	for depth := 1; depth > 0; {
+		t := c.next()
+		switch {
+		case t.kind == wgslEOF:
+			return wgslErrorf(open, ErrWGSLParse, "unclosed function body")
+		case t.kind == wgslIdent:
+			f.refs[t.text] = true
+			if !c.is("(") {
+				break
+			}
+			args := callArgs(c.toks[c.pos:])
+			if !wgslSampleBuiltins[t.text] {
+				f.calls = append(f.calls, wgslCall{callee: t.text, args: args})
+				break
+			}
+			// The first two arguments hold the texture and sampler, or for
+			// textureGather the component and texture.
+			for _, arg := range args[:min(len(args), 2)] {
+				for _, name := range arg {
+					f.sampled[name] = true
+				}
+			}
+		case t.text == "{":
+			depth++
+		case t.text == "}":
+			depth--
+		}
+	}
+
+	p.funcs[name.text] = f
+	if findAttr(attrs, "vertex") != nil || findAttr(attrs, "fragment") != nil || findAttr(attrs, "compute") != nil {
+		p.entries = append(p.entries, f)
+	}
+	return nil
+}
+
+// wgslSampleBuiltins are the builtins that read a texture through a
+// filtering sampler.
+var wgslSampleBuiltins = map[string]bool{
+	"textureSample":                true,
+	"textureSampleBias":            true,
+	"textureSampleLevel":           true,
+	"textureSampleGrad":            true,
+	"textureSampleBaseClampToEdge": true,
+	"textureGather":                true,
+}
+
+// callArgs returns the identifiers in each argument of the call whose "("
+// starts toks, without consuming them.
+func callArgs(toks []wgslToken) [][]string {
+	args := [][]string{nil}
+	depth := 0
+	for _, t := range toks {
+		switch {
+		case t.kind == wgslEOF:
+			return args
+		case t.kind == wgslIdent:
+			args[len(args)-1] = append(args[len(args)-1], t.text)
+		case t.kind != wgslPunct:
+		case t.text == "(" || t.text == "[":
+			depth++
+		case t.text == ")" || t.text == "]":
+			if depth--; depth == 0 {
+				return args
+			}
+		case t.text == "," && depth == 1:
+			args = append(args, nil)
+		}
+	}
+	return args
+}
+
+// parseVar parses a module-scope variable, keeping those with @group and
+// @binding.
+func (p *wgslParser) parseVar(at wgslToken, attrs []wgslAttr) error {
+	c := &p.c
+	v := &wgslVar{}
+	if c.is("<") {
+		args, err := c.list("<", ">")
+		if err != nil {
+			return err
+		}
+		for i, arg := range args {
+			if len(arg) != 1 || arg[0].kind != wgslIdent {
+				return wgslErrorf(arg[0], ErrWGSLParse, "expected address space or access mode")
+			}
+			if i == 0 {
+				v.addressSpace = arg[0].text
+			} else {
+				v.access = arg[0].text
+			}
+			v.addressTokens = append(v.addressTokens, arg[0])
+		}
+	}
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	v.name = name
+	if c.accept(":") {
+		if v.typ, err = p.parseType(c); err != nil {
+			return err
+		}
+		v.hasType = true
+	}
+	if err := c.skipPast(";"); err != nil {
+		return err
+	}
+
+	group, binding := findAttr(attrs, "group"), findAttr(attrs, "binding")
+	if group == nil && binding == nil {
+		return nil
+	}
+	if group == nil || binding == nil || len(group.args) != 1 || len(binding.args) != 1 {
+		return wgslErrorf(at, ErrWGSLParse, "resource %s needs @group(n) and @binding(n)", name.text)
+	}
+	if !v.hasType {
+		return wgslErrorf(name, ErrWGSLParse, "resource %s has no type", name.text)
+	}
+	v.group, v.binding = group.args[0], binding.args[0]
+	p.vars = append(p.vars, v)
+	return nil
+}
+
+// parseConst parses "const name [: type] = expr;".
+func (p *wgslParser) parseConst() error {
+	c := &p.c
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	if c.accept(":") {
+		if _, err := p.parseType(c); err != nil {
+			return err
+		}
+	}
+	if _, err := c.expect("="); err != nil {
+		return err
+	}
+	start := c.pos
+	if err := c.skipPast(";"); err != nil {
+		return err
+	}
+	p.consts[name.text] = c.toks[start : c.pos-1]
+	return nil
+}
+
+// parseOverride parses "override name [: type] [= expr];".
+func (p *wgslParser) parseOverride(attrs []wgslAttr) error {
+	c := &p.c
+	name, err := c.ident()
+	if err != nil {
+		return err
+	}
+	o := &ShaderOverride{Name: name.text}
+	if id := findAttr(attrs, "id"); id != nil {
+		if len(id.args) != 1 {
+			return wgslErrorf(id.tok, ErrWGSLParse, "@id needs one argument")
+		}
+		p.overrideIDs[o.Name] = id.args[0]
+		o.HasID = true
+	}
+	if c.accept(":") {
+		t, err := p.parseType(c)
+		if err != nil {
+			return err
+		}
+		o.Type = p.typeString(t)
+	}
+	if c.accept("=") {
+		start := c.pos
+		if err := c.skipPast(";"); err != nil {
+			return err
+		}
+		init := c.toks[start : c.pos-1]
+		if len(init) == 0 {
+			return wgslErrorf(name, ErrWGSLParse, "override %s has an empty initializer", name.text)
+		}
+		last := init[len(init)-1]
+		o.Default = p.src[init[0].off : last.off+len(last.text)]
+		p.overrideInits[o.Name] = init
+		if o.Type == "" && len(init) == 1 {
+			o.Type = wgslLiteralType(init[0])
+		}
+	} else if _, err := c.expect(";"); err != nil {
+		return err
+	}
+	p.overrides[o.Name] = o
+	p.overList = append(p.overList, o)
+	return nil
+}
+
+// wgslLiteralType returns the concrete type of a literal, or "".
+func wgslLiteralType(t wgslToken) string {
+	if t.kind == wgslIdent {
+		if t.text == "true" || t.text == "false" {
+			return "bool"
+		}
+		return ""
+	}
+	s := t.text
+	hex := strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+	switch last := s[len(s)-1]; {
+	case last == 'u':
+		return "u32"
+	case last == 'i':
+		return "i32"
+	case last == 'h':
+		return "f16"
+	case last == 'f' && !hex:
+		return "f32"
+	case strings.ContainsAny(s, ".pP") || !hex && strings.ContainsAny(s, "eE"):
+		return "f32"
+	default:
+		return "i32"
+	}
+}
+
+// evalUint32 evaluates a constant integer expression that must fit uint32.
+func (p *wgslParser) evalUint32(expr []wgslToken) (uint32, error) {
+	v, err := p.evalInt(expr)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 0xFFFFFFFF {
+		return 0, wgslErrorf(expr[0], ErrWGSLParse, "value %d out of range", v)
+	}
+	return uint32(v), nil
+}
+
+// evalInt evaluates a constant integer expression made of literals, const
+// names, i32/u32 conversions, parentheses and + - * / %.
+func (p *wgslParser) evalInt(expr []wgslToken) (int64, error) {
+	if len(expr) == 0 {
+		return 0, wgslErrorf(p.c.peek(), ErrWGSLParse, "empty expression")
+	}
+	c := wgslCursor{toks: expr}
+	v, err := p.evalSum(&c)
+	if err == nil && !c.eof() {
+		err = wgslErrorf(c.peek(), ErrWGSLParse, "unexpected %s in expression", c.peek().describe())
+	}
+	return v, err
+}
+
+// evalSum evaluates "term {(+|-) term}".
+func (p *wgslParser) evalSum(c *wgslCursor) (int64, error) {
+	v, err := p.evalProduct(c)
+	for err == nil && (c.is("+") || c.is("-")) {
+		op := c.next().text
+		var r int64
+		if r, err = p.evalProduct(c); op == "+" {
+			v += r
+		} else {
+			v -= r
+		}
+	}
+	return v, err
+}
+
+// evalProduct evaluates "unary {(*|/|%) unary}".
+func (p *wgslParser) evalProduct(c *wgslCursor) (int64, error) {
+	v, err := p.evalUnary(c)
+	for err == nil && (c.is("*") || c.is("/") || c.is("%")) {
+		op := c.next()
+		var r int64
+		if r, err = p.evalUnary(c); err != nil {
+			break
+		}
+		switch {
+		case op.text == "*":
+			v *= r
+		case r == 0:
+			return 0, wgslErrorf(op, ErrWGSLParse, "division by zero")
+		case op.text == "/":
+			v /= r
+		default:
+			v %= r
+		}
+	}
+	return v, err
+}
+
+// evalUnary evaluates a negation, parenthesized expression, literal,
+// conversion or const name.
+func (p *wgslParser) evalUnary(c *wgslCursor) (int64, error) {
+	t := c.next()
+	switch {
+	case t.kind == wgslPunct && t.text == "-":
+		v, err := p.evalUnary(c)
+		return -v, err
+	case t.kind == wgslPunct && t.text == "(":
+		v, err := p.evalSum(c)
+		if err == nil {
+			_, err = c.expect(")")
+		}
+		return v, err
+	case t.kind == wgslNumber:
+		return parseWGSLInt(t)
+	case t.kind == wgslIdent && (t.text == "i32" || t.text == "u32") && c.is("("):
+		return p.evalUnary(c)
+	case t.kind == wgslIdent:
+		if init, ok := p.consts[t.text]; ok {
+			if p.evalDepth > 32 {
+				return 0, wgslErrorf(t, ErrWGSLParse, "const %s is recursive", t.text)
+			}
+			p.evalDepth++
+			defer func() { p.evalDepth-- }()
+			return p.evalInt(init)
+		}
+		if _, ok := p.overrides[t.text]; ok {
+			return 0, wgslErrorf(t, ErrWGSLUnsupported, "expression depends on override %s", t.text)
+		}
+		return 0, wgslErrorf(t, ErrWGSLParse, "%s is not a constant", t.text)
+	default:
+		return 0, wgslErrorf(t, ErrWGSLParse, "unexpected %s in expression", t.describe())
+	}
+}
+
+// parseWGSLInt parses an integer literal with an optional i or u suffix.
+func parseWGSLInt(t wgslToken) (int64, error) {
+	s := strings.TrimRight(t.text, "iu")
+	var (
+		v   int64
+		err error
+	)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		v, err = strconv.ParseInt(s[2:], 16, 64)
+	} else {
+		v, err = strconv.ParseInt(s, 10, 64)
+	}
+	if err != nil {
+		return 0, wgslErrorf(t, ErrWGSLParse, "%s is not an integer", t.text)
+	}
+	return v, nil
+}
+
+// resolve expands aliases, including the predeclared ones such as vec4f
+// and mat4x4h.
+func (p *wgslParser) resolve(t wgslType) (wgslType, error) {
+	for range 64 {
+		if a, ok := p.aliases[t.name]; ok && len(t.args) == 0 {
+			t = a
+			continue
+		}
+		if base, scalar, ok := wgslPredeclaredAlias(t.name); ok && len(t.args) == 0 {
+			arg := wgslToken{kind: wgslIdent, text: scalar, off: t.tok.off, line: t.tok.line, col: t.tok.col}
+			t = wgslType{tok: t.tok, name: base, args: [][]wgslToken{{arg}}}
+		}
+		return t, nil
+	}
+	return t, wgslErrorf(t.tok, ErrWGSLParse, "alias %s is recursive", t.name)
+}
+
+// wgslPredeclaredAlias splits a predeclared alias such as vec3u or
+// mat2x4f into its generic type and scalar.
+func wgslPredeclaredAlias(name string) (base, scalar string, ok bool) {
+	scalars := map[byte]string{'f': "f32", 'h': "f16", 'i': "i32", 'u': "u32"}
+	n := len(name)
+	switch {
+	case n == 5 && strings.HasPrefix(name, "vec") && name[3] >= '2' && name[3] <= '4':
+		scalar, ok = scalars[name[4]]
+	case n == 7 && strings.HasPrefix(name, "mat") && name[3] >= '2' && name[3] <= '4' && name[4] == 'x' && name[5] >= '2' && name[5] <= '4':
+		scalar, ok = scalars[name[6]]
+		ok = ok && name[6] != 'i' && name[6] != 'u'
+	}
+	if !ok {
+		return "", "", false
+	}
+	return name[:n-1], scalar, true
+}
+
+// typeString formats t with aliases expanded, e.g. "array<vec4<f32>, 4>".
+func (p *wgslParser) typeString(t wgslType) string {
+	if r, err := p.resolve(t); err == nil {
+		t = r
+	}
+	if len(t.args) == 0 {
+		return t.name
+	}
+	args := make([]string, len(t.args))
+	for i, arg := range t.args {
+		if arg[0].kind == wgslIdent {
+			if at, err := p.typeArg(t, i); err == nil {
+				args[i] = p.typeString(at)
+				continue
+			}
+		}
+		var b strings.Builder
+		for _, tok := range arg {
+			b.WriteString(tok.text)
+		}
+		args[i] = b.String()
+	}
+	return t.name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// vectorSize returns the component count of a vecN name, or 0.
+func vectorSize(name string) uint64 {
+	if len(name) == 4 && strings.HasPrefix(name, "vec") && name[3] >= '2' && name[3] <= '4' {
+		return uint64(name[3] - '0')
+	}
+	return 0
+}
+
+// layout returns the size and alignment of a host-shareable type, counting
+// one element of a runtime-sized array.
+func (p *wgslParser) layout(t wgslType, depth int) (size, align uint64, err error) {
+	if depth > 64 {
+		return 0, 0, wgslErrorf(t.tok, ErrWGSLParse, "type %s is recursive", t.name)
+	}
+	if t, err = p.resolve(t); err != nil {
+		return 0, 0, err
+	}
+	elem := func() (size, align uint64, err error) {
+		e, err := p.typeArg(t, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+		return p.layout(e, depth+1)
+	}
+
+	switch name := t.name; {
+	case name == "f32" || name == "i32" || name == "u32":
+		return 4, 4, nil
+	case name == "f16":
+		return 2, 2, nil
+	case name == "atomic":
+		return 4, 4, nil
+	case vectorSize(name) > 0:
+		s, _, err := elem()
+		n := vectorSize(name)
+		if n == 3 {
+			return 3 * s, 4 * s, err
+		}
+		return n * s, n * s, err
+	case len(name) == 6 && strings.HasPrefix(name, "mat") && name[4] == 'x':
+		cols, rows := uint64(name[3]-'0'), uint64(name[5]-'0')
+		s, _, err := elem()
+		colAlign := rows * s
+		if rows == 3 {
+			colAlign = 4 * s
+		}
+		return cols * alignUp(rows*s, colAlign), colAlign, err
+	case name == "array":
+		s, a, err := elem()
+		if err != nil {
+			return 0, 0, err
+		}
+		stride, n := alignUp(s, a), uint64(1)
+		if len(t.args) > 1 {
+			count, err := p.evalUint32(t.args[1])
+			if err != nil {
+				return 0, 0, err
+			}
+			n = uint64(count)
+		}
+		return n * stride, a, nil
+	}
+
+	st, ok := p.structs[t.name]
+	if !ok {
+		return 0, 0, wgslErrorf(t.tok, ErrWGSLParse, "%s is not a host-shareable type", t.name)
+	}
+	var offset, structAlign uint64 = 0, 1
+	for _, m := range st.members {
+		s, a, err := p.layout(m.typ, depth+1)
+		if err != nil {
+			return 0, 0, err
+		}
+		if attr := findAttr(m.attrs, "align"); attr != nil && len(attr.args) == 1 {
+			n, err := p.evalUint32(attr.args[0])
+			if err != nil {
+				return 0, 0, err
+			}
+			a = uint64(n)
+		}
+		if attr := findAttr(m.attrs, "size"); attr != nil && len(attr.args) == 1 {
+			n, err := p.evalUint32(attr.args[0])
+			if err != nil {
+				return 0, 0, err
+			}
+			s = uint64(n)
+		}
+		if a == 0 {
+			return 0, 0, wgslErrorf(m.name, ErrWGSLParse, "member %s has zero alignment", m.name.text)
+		}
+		offset = alignUp(offset, a) + s
+		structAlign = max(structAlign, a)
+	}
+	return alignUp(offset, structAlign), structAlign, nil
+}
+
+// wgslTexelFormats maps WGSL storage texel formats to texture formats.
+var wgslTexelFormats = map[string]TextureFormat{
+	"rgba8unorm":  TextureFormatRGBA8Unorm,
+	"rgba8snorm":  TextureFormatRGBA8Snorm,
+	"rgba8uint":   TextureFormatRGBA8Uint,
+	"rgba8sint":   TextureFormatRGBA8Sint,
+	"rgba16uint":  TextureFormatRGBA16Uint,
+	"rgba16sint":  TextureFormatRGBA16Sint,
+	"rgba16float": TextureFormatRGBA16Float,
+	"r32uint":     TextureFormatR32Uint,
+	"r32sint":     TextureFormatR32Sint,
+	"r32float":    TextureFormatR32Float,
+	"rg32uint":    TextureFormatRG32Uint,
+	"rg32sint":    TextureFormatRG32Sint,
+	"rg32float":   TextureFormatRG32Float,
+	"rgba32uint":  TextureFormatRGBA32Uint,
+	"rgba32sint":  TextureFormatRGBA32Sint,
+	"rgba32float": TextureFormatRGBA32Float,
+	"bgra8unorm":  TextureFormatBGRA8Unorm,
+}
+
+// wgslViewDimensions maps texture type suffixes to view dimensions.
+var wgslViewDimensions = map[string]TextureViewDimension{
+	"1d":              TextureViewDimension1D,
+	"2d":              TextureViewDimension2D,
+	"2d_array":        TextureViewDimension2DArray,
+	"3d":              TextureViewDimension3D,
+	"cube":            TextureViewDimensionCube,
+	"cube_array":      TextureViewDimensionCubeArray,
+	"multisampled_2d": TextureViewDimension2D,
+}
+
+// bindingEntry maps a resource variable to its layout entry, without
+// Visibility.
+func (p *wgslParser) bindingEntry(v *wgslVar) (BindGroupLayoutEntry, error) {
+	var e BindGroupLayoutEntry
+	binding, err := p.evalUint32(v.binding)
+	if err != nil {
+		return e, err
+	}
+	e.Binding = binding
+
+	switch v.addressSpace {
+	case "uniform", "storage":
+		size, _, err := p.layout(v.typ, 0)
+		if err != nil {
+			return e, err
+		}
+		e.Buffer = &BufferBindingLayout{Type: BufferBindingTypeUniform, MinBindingSize: size}
+		if v.addressSpace == "storage" {
+			switch v.access {
+			case "", "read":
+				e.Buffer.Type = BufferBindingTypeReadOnlyStorage
+			case "read_write":
+				e.Buffer.Type = BufferBindingTypeStorage
+			default:
+				return e, wgslErrorf(v.addressTokens[1], ErrWGSLParse, "invalid storage access %s", v.access)
+			}
+		}
+		return e, nil
+	case "":
+	default:
+		return e, wgslErrorf(v.addressTokens[0], ErrWGSLParse, "resource %s in address space %s", v.name.text, v.addressSpace)
+	}
+
+	t, err := p.resolve(v.typ)
+	if err != nil {
+		return e, err
+	}
+	if t.name == "binding_array" {
+		if len(t.args) < 2 {
+			return e, wgslErrorf(t.tok, ErrWGSLUnsupported, "binding_array without a count")
+		}
+		if e.Count, err = p.evalUint32(t.args[1]); err != nil {
+			return e, err
+		}
+		if t, err = p.typeArg(t, 0); err != nil {
+			return e, err
+		}
+		if t, err = p.resolve(t); err != nil {
+			return e, err
+		}
+	}
+	return e, p.handleEntry(&e, t)
+}
+
+// handleEntry fills e for a sampler or texture type.
+func (p *wgslParser) handleEntry(e *BindGroupLayoutEntry, t wgslType) error {
+	switch name := t.name; {
+	case name == "sampler":
+		e.Sampler = &SamplerBindingLayout{Type: SamplerBindingTypeFiltering}
+	case name == "sampler_comparison":
+		e.Sampler = &SamplerBindingLayout{Type: SamplerBindingTypeComparison}
+	case strings.HasPrefix(name, "texture_depth_"):
+		dim, ok := wgslViewDimensions[strings.TrimPrefix(name, "texture_depth_")]
+		if !ok || dim == TextureViewDimension1D || dim == TextureViewDimension3D {
+			return wgslErrorf(t.tok, ErrWGSLParse, "unknown texture type %s", name)
+		}
+		e.Texture = &TextureBindingLayout{SampleType: TextureSampleTypeDepth, ViewDimension: dim,
+			Multisampled: name == "texture_depth_multisampled_2d"}
+	case strings.HasPrefix(name, "texture_storage_"):
+		dim, ok := wgslViewDimensions[strings.TrimPrefix(name, "texture_storage_")]
+		if !ok || dim == TextureViewDimensionCube || dim == TextureViewDimensionCubeArray || strings.HasSuffix(name, "multisampled_2d") {
+			return wgslErrorf(t.tok, ErrWGSLParse, "unknown texture type %s", name)
+		}
+		format, err := p.identArg(t, 0)
+		if err != nil {
+			return err
+		}
+		access, err := p.identArg(t, 1)
+		if err != nil {
+			return err
+		}
+		st := &StorageTextureBindingLayout{ViewDimension: dim}
+		if st.Format, ok = wgslTexelFormats[format.text]; !ok {
+			return wgslErrorf(format, ErrWGSLUnsupported, "texel format %s", format.text)
+		}
+		switch access.text {
+		case "read":
+			st.Access = StorageTextureAccessReadOnly
+		case "write":
+			st.Access = StorageTextureAccessWriteOnly
+		case "read_write":
+			st.Access = StorageTextureAccessReadWrite
+		default:
+			return wgslErrorf(access, ErrWGSLParse, "invalid access mode %s", access.text)
+		}
+		e.StorageTexture = st
+	case name == "texture_external":
+		return wgslErrorf(t.tok, ErrWGSLUnsupported, "texture_external")
+	case strings.HasPrefix(name, "texture_"):
+		dim, ok := wgslViewDimensions[strings.TrimPrefix(name, "texture_")]
+		if !ok {
+			return wgslErrorf(t.tok, ErrWGSLParse, "unknown texture type %s", name)
+		}
+		sampled, err := p.typeArg(t, 0)
+		if err != nil {
+			return err
+		}
+		tex := &TextureBindingLayout{ViewDimension: dim, Multisampled: name == "texture_multisampled_2d"}
+		switch sampled.name {
+		case "f32":
+			tex.SampleType = TextureSampleTypeFloat
+			if tex.Multisampled {
+				tex.SampleType = TextureSampleTypeUnfilterableFloat
+			}
+		case "i32":
+			tex.SampleType = TextureSampleTypeSint
+		case "u32":
+			tex.SampleType = TextureSampleTypeUint
+		default:
+			return wgslErrorf(sampled.tok, ErrWGSLParse, "invalid sampled type %s", sampled.name)
+		}
+		e.Texture = tex
+	default:
+		return wgslErrorf(t.tok, ErrWGSLParse, "%s is not a resource type", name)
+	}
+	return nil
+}
+
+// wgslVertexFormats maps WGSL types to the vertex formats they read unconverted.
+var wgslVertexFormats = map[string]VertexFormat{
+	"f32":       VertexFormatFloat32,
+	"vec2<f32>": VertexFormatFloat32x2,
+	"vec3<f32>": VertexFormatFloat32x3,
+	"vec4<f32>": VertexFormatFloat32x4,
+	"u32":       VertexFormatUint32,
+	"vec2<u32>": VertexFormatUint32x2,
+	"vec3<u32>": VertexFormatUint32x3,
+	"vec4<u32>": VertexFormatUint32x4,
+	"i32":       VertexFormatSint32,
+	"vec2<i32>": VertexFormatSint32x2,
+	"vec3<i32>": VertexFormatSint32x3,
+	"vec4<i32>": VertexFormatSint32x4,
+	"f16":       VertexFormatFloat16,
+	"vec2<f16>": VertexFormatFloat16x2,
+	"vec4<f16>": VertexFormatFloat16x4,
+}
+
+// appendIO appends the @location values of a parameter or result, looking
+// into struct types.
+func (p *wgslParser) appendIO(ios []ShaderIO, name string, attrs []wgslAttr, t wgslType) ([]ShaderIO, error) {
+	if loc := findAttr(attrs, "location"); loc != nil {
+		if len(loc.args) != 1 {
+			return nil, wgslErrorf(loc.tok, ErrWGSLParse, "@location needs one argument")
+		}
+		n, err := p.evalUint32(loc.args[0])
+		if err != nil {
+			return nil, err
+		}
+		typ := p.typeString(t)
+		return append(ios, ShaderIO{Name: name, Location: n, Type: typ, Format: wgslVertexFormats[typ]}), nil
+	}
+	if findAttr(attrs, "builtin") != nil {
+		return ios, nil
+	}
+	r, err := p.resolve(t)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := p.structs[r.name]
+	if !ok {
+		return nil, wgslErrorf(t.tok, ErrWGSLParse, "entry point I/O %s needs @location or @builtin", cmp.Or(name, "result"))
+	}
+	for _, m := range st.members {
+		if ios, err = p.appendIO(ios, m.name.text, m.attrs, m.typ); err != nil {
+			return nil, err
+		}
+	}
+	return ios, nil
+}
+
+// entryPoint reflects an entry point function.
+func (p *wgslParser) entryPoint(f *wgslFunc) (ShaderEntryPoint, error) {
+	ep := ShaderEntryPoint{Name: f.name.text}
+	switch {
+	case findAttr(f.attrs, "vertex") != nil:
+		ep.Stage = ShaderStageVertex
+	case findAttr(f.attrs, "fragment") != nil:
+		ep.Stage = ShaderStageFragment
+	default:
+		ep.Stage = ShaderStageCompute
+		ws := findAttr(f.attrs, "workgroup_size")
+		if ws == nil || len(ws.args) == 0 || len(ws.args) > 3 {
+			return ep, wgslErrorf(f.name, ErrWGSLParse, "compute entry point %s needs @workgroup_size with 1 to 3 arguments", f.name.text)
+		}
+		ep.WorkgroupSize = WorkgroupSize{1, 1, 1}
+		for i, arg := range ws.args {
+			if o, ok := p.overrides[arg[0].text]; ok && len(arg) == 1 {
+				ep.WorkgroupSizeOverrides[i] = o.Name
+				ep.WorkgroupSize[i] = 0
+				if init := p.overrideInits[o.Name]; init != nil {
+					if n, err := p.evalUint32(init); err == nil {
+						ep.WorkgroupSize[i] = n
+					}
+				}
+				continue
+			}
+			n, err := p.evalUint32(arg)
+			if err != nil {
+				return ep, err
+			}
+			ep.WorkgroupSize[i] = n
+		}
+	}
+
+	var err error
+	for _, param := range f.params {
+		if ep.Inputs, err = p.appendIO(ep.Inputs, param.name.text, param.attrs, param.typ); err != nil {
+			return ep, err
+		}
+	}
+	if f.result != nil {
+		if ep.Outputs, err = p.appendIO(ep.Outputs, "", f.resultAttrs, *f.result); err != nil {
+			return ep, err
+		}
+	}
+	byLocation := func(a, b ShaderIO) int { return cmp.Compare(a.Location, b.Location) }
+	slices.SortStableFunc(ep.Inputs, byLocation)
+	slices.SortStableFunc(ep.Outputs, byLocation)
+	return ep, nil
+}
+
+// uses returns the identifiers reachable from f through function calls.
+func (p *wgslParser) uses(f *wgslFunc) map[string]bool {
+	seen := map[string]bool{f.name.text: true}
+	queue := []*wgslFunc{f}
+	for len(queue) > 0 {
+		fn := queue[0]
+		queue = queue[1:]
+		for name := range fn.refs {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if callee, ok := p.funcs[name]; ok {
+				queue = append(queue, callee)
+			}
+		}
+	}
+	return seen
+}
+
+// samples returns the identifiers f passes to a builtin in
+// wgslSampleBuiltins, directly or through the parameters of the user
+// functions it calls. memo holds the results for functions already seen.
+func (p *wgslParser) samples(f *wgslFunc, memo map[*wgslFunc]map[string]bool) map[string]bool {
+	if s, ok := memo[f]; ok {
+		return s
+	}
+	s := make(map[string]bool, len(f.sampled))
+	memo[f] = s
+	for name := range f.sampled {
+		s[name] = true
+	}
+	for _, call := range f.calls {
+		callee, ok := p.funcs[call.callee]
+		if !ok {
+			continue
+		}
+		inner := p.samples(callee, memo)
+		for name := range inner {
+			if !slices.ContainsFunc(callee.params, func(m wgslMember) bool { return m.name.text == name }) {
+				s[name] = true
+			}
+		}
+		for i, param := range callee.params {
+			if i < len(call.args) && inner[param.name.text] {
+				for _, name := range call.args[i] {
+					s[name] = true
+				}
+			}
+		}
+	}
+	return s
+}
+
+// reflect builds the ShaderReflection from the parsed declarations.
+func (p *wgslParser) reflect() (*ShaderReflection, error) {
+	r := &ShaderReflection{}
+	uses := make([]map[string]bool, len(p.entries))
+	samples := make([]map[string]bool, len(p.entries))
+	memo := make(map[*wgslFunc]map[string]bool)
+	for i, f := range p.entries {
+		ep, err := p.entryPoint(f)
+		if err != nil {
+			return nil, err
+		}
+		r.EntryPoints = append(r.EntryPoints, ep)
+		uses[i] = p.uses(f)
+		samples[i] = p.samples(f, memo)
+	}
+
+	for _, v := range p.vars {
+		group, err := p.evalUint32(v.group)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := p.bindingEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		b := ShaderBinding{Name: v.name.text, Group: group, Type: p.typeString(v.typ), Entry: entry}
+		for i, ep := range r.EntryPoints {
+			if uses[i][b.Name] {
+				b.EntryPoints = append(b.EntryPoints, ep.Name)
+				b.Entry.Visibility |= ep.Stage
+			}
+			if samples[i][b.Name] {
+				b.SampledBy = append(b.SampledBy, ep.Name)
+			}
+		}
+		b.Entry.Texture = b.sampleType(b.SampledBy)
+		r.Bindings = append(r.Bindings, b)
+	}
+	slices.SortStableFunc(r.Bindings, func(a, b ShaderBinding) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.Entry.Binding, b.Entry.Binding))
+	})
+
+	for _, o := range p.overList {
+		if id, ok := p.overrideIDs[o.Name]; ok {
+			n, err := p.evalUint32(id)
+			if err != nil {
+				return nil, err
+			}
+			o.ID = n
+		}
+		r.Overrides = append(r.Overrides, *o)
+	}
+	return r, nil
+}
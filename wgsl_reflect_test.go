@@ -0,0 +1,313 @@
+package gputypes
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+const testReflectWGSL = `enable f16;
+
+// Camera data.
+struct Camera {
+    view_proj: mat4x4f,
+    position: vec3<f32>,
+    exposure: f32,
+}
+
+struct Light { color: vec3f, intensity: f32 }
+
+alias LightArray = array<Light>;
+
+@id(7) override wg_x: u32 = 64;
+override gamma = 2.2;
+override use_fog: bool;
+
+@group(0) @binding(0) var<uniform> camera: Camera;
+@group(0) @binding(1) var<storage, read> lights: LightArray;
+@group(1) @binding(0) var albedo: texture_2d<f32>;
+@group(1) @binding(1) var albedo_sampler: sampler;
+@group(1) @binding(2) var shadow: texture_depth_2d_array;
+@group(1) @binding(3) var shadow_sampler: sampler_comparison;
+@group(2) @binding(0) var output: texture_storage_2d<rgba16float, write>;
+@group(2) @binding(1) var<storage, read_write> counters: array<atomic<u32>, MAX_LIGHTS * 2>;
+@group(2) @binding(2) var materials: binding_array<texture_2d<u32>, 16>;
+@group(3) @binding(0) var<uniform> unused: vec4<f32>;
+
+const MAX_LIGHTS = 4u;
+const TILE = 8;
+
+struct VertexInput {
+    @location(0) position: vec3f,
+    @location(1) normal: vec3<f32>,
+    @builtin(vertex_index) index: u32,
+}
+
+struct VertexOutput {
+    @builtin(position) clip: vec4f,
+    @location(1) uv: vec2<f32>,
+    @location(0) @interpolate(flat) id: u32,
+}
+
+fn shade(n: vec3f) -> vec3f {
+    return lights[0].color * max(dot(n, vec3f(0.0, 1.0, 0.0)), 0.0);
+}
+
+@vertex
+fn vs_main(in: VertexInput, @location(2) uv: vec2h) -> VertexOutput {
+    var out: VertexOutput;
+    out.clip = camera.view_proj * vec4f(in.position, 1.0);
+    return out;
+}
+
+@fragment
+fn fs_main(in: VertexOutput) -> @location(0) vec4<f32> {
+    let c = textureSample(albedo, albedo_sampler, in.uv);
+    return vec4f(shade(c.rgb), 1.0);
+}
+
+@compute @workgroup_size(wg_x, TILE / 2)
+fn cs_main(@builtin(global_invocation_id) id: vec3u) {
+    /* nested /* comment */ */
+    atomicAdd(&counters[0], 1u);
+    textureStore(output, id.xy, vec4f(textureLoad(materials[0], id.xy, 0)));
+}
+`
+
+func TestReflectWGSL(t *testing.T) {
+	r, err := ReflectWGSL(ShaderSourceWGSL{Code: testReflectWGSL})
+	if err != nil {
+		t.Fatalf("ReflectWGSL() error = %v", err)
+	}
+
+	if len(r.EntryPoints) != 3 {
+		t.Fatalf("EntryPoints = %+v, want 3", r.EntryPoints)
+	}
+	vs, _ := r.EntryPoint("vs_main")
+	wantInputs := []ShaderIO{
+		{"position", 0, "vec3<f32>", VertexFormatFloat32x3},
+		{"normal", 1, "vec3<f32>", VertexFormatFloat32x3},
+		{"uv", 2, "vec2<f16>", VertexFormatFloat16x2},
+	}
+	wantOutputs := []ShaderIO{
+		{"id", 0, "u32", VertexFormatUint32},
+		{"uv", 1, "vec2<f32>", VertexFormatFloat32x2},
+	}
+	if vs == nil || vs.Stage != ShaderStageVertex || !slices.Equal(vs.Inputs, wantInputs) || !slices.Equal(vs.Outputs, wantOutputs) {
+		t.Errorf("vs_main = %+v", vs)
+	}
+	fs, _ := r.EntryPoint("fs_main")
+	if fs == nil || fs.Stage != ShaderStageFragment || !slices.Equal(fs.Inputs, wantOutputs) ||
+		!slices.Equal(fs.Outputs, []ShaderIO{{"", 0, "vec4<f32>", VertexFormatFloat32x4}}) {
+		t.Errorf("fs_main = %+v", fs)
+	}
+	cs, _ := r.EntryPoint("cs_main")
+	if cs == nil || cs.Stage != ShaderStageCompute || cs.WorkgroupSize != (WorkgroupSize{64, 4, 1}) ||
+		cs.WorkgroupSizeOverrides != [3]string{"wg_x", "", ""} || len(cs.Inputs) != 0 {
+		t.Errorf("cs_main = %+v", cs)
+	}
+	if _, ok := r.EntryPoint("shade"); ok {
+		t.Error("EntryPoint(shade) found a plain function")
+	}
+
+	type binding struct {
+		name    string
+		group   uint32
+		typ     string
+		entry   BindGroupLayoutEntry
+		targets []string
+	}
+	want := []binding{
+		{"camera", 0, "Camera", BindGroupLayoutEntry{Binding: 0, Visibility: ShaderStageVertex,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeUniform, MinBindingSize: 80}}, []string{"vs_main"}},
+		{"lights", 0, "array<Light>", BindGroupLayoutEntry{Binding: 1, Visibility: ShaderStageFragment,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeReadOnlyStorage, MinBindingSize: 16}}, []string{"fs_main"}},
+		{"albedo", 1, "texture_2d<f32>", BindGroupLayoutEntry{Binding: 0, Visibility: ShaderStageFragment,
+			Texture: &TextureBindingLayout{SampleType: TextureSampleTypeFloat, ViewDimension: TextureViewDimension2D}}, []string{"fs_main"}},
+		{"albedo_sampler", 1, "sampler", BindGroupLayoutEntry{Binding: 1, Visibility: ShaderStageFragment,
+			Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeFiltering}}, []string{"fs_main"}},
+		{"shadow", 1, "texture_depth_2d_array", BindGroupLayoutEntry{Binding: 2,
+			Texture: &TextureBindingLayout{SampleType: TextureSampleTypeDepth, ViewDimension: TextureViewDimension2DArray}}, nil},
+		{"shadow_sampler", 1, "sampler_comparison", BindGroupLayoutEntry{Binding: 3,
+			Sampler: &SamplerBindingLayout{Type: SamplerBindingTypeComparison}}, nil},
+		{"output", 2, "texture_storage_2d<rgba16float, write>", BindGroupLayoutEntry{Binding: 0, Visibility: ShaderStageCompute,
+			StorageTexture: &StorageTextureBindingLayout{Access: StorageTextureAccessWriteOnly, Format: TextureFormatRGBA16Float,
+				ViewDimension: TextureViewDimension2D}}, []string{"cs_main"}},
+		{"counters", 2, "array<atomic<u32>, MAX_LIGHTS*2>", BindGroupLayoutEntry{Binding: 1, Visibility: ShaderStageCompute,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeStorage, MinBindingSize: 32}}, []string{"cs_main"}},
+		{"materials", 2, "binding_array<texture_2d<u32>, 16>", BindGroupLayoutEntry{Binding: 2, Visibility: ShaderStageCompute, Count: 16,
+			Texture: &TextureBindingLayout{SampleType: TextureSampleTypeUint, ViewDimension: TextureViewDimension2D}}, []string{"cs_main"}},
+		{"unused", 3, "vec4<f32>", BindGroupLayoutEntry{Binding: 0,
+			Buffer: &BufferBindingLayout{Type: BufferBindingTypeUniform, MinBindingSize: 16}}, nil},
+	}
+	if len(r.Bindings) != len(want) {
+		t.Fatalf("Bindings = %d, want %d", len(r.Bindings), len(want))
+	}
+	for i, w := range want {
+		b := r.Bindings[i]
+		if b.Name != w.name || b.Group != w.group || b.Type != w.typ || !slices.Equal(b.EntryPoints, w.targets) ||
+			!sameLayoutEntry(b.Entry, w.entry) {
+			t.Errorf("Bindings[%d] = %+v, want %+v", i, b, w)
+		}
+	}
+
+	wantOverrides := []ShaderOverride{
+		{Name: "wg_x", ID: 7, HasID: true, Type: "u32", Default: "64"},
+		{Name: "gamma", Type: "f32", Default: "2.2"},
+		{Name: "use_fog", Type: "bool"},
+	}
+	if !slices.Equal(r.Overrides, wantOverrides) {
+		t.Errorf("Overrides = %+v, want %+v", r.Overrides, wantOverrides)
+	}
+}
+
+// sameLayoutEntry compares layout entries by value.
+func sameLayoutEntry(a, b BindGroupLayoutEntry) bool {
+	same := func() bool {
+		switch {
+		case a.Buffer != nil && b.Buffer != nil:
+			return *a.Buffer == *b.Buffer
+		case a.Sampler != nil && b.Sampler != nil:
+			return *a.Sampler == *b.Sampler
+		case a.Texture != nil && b.Texture != nil:
+			return *a.Texture == *b.Texture
+		case a.StorageTexture != nil && b.StorageTexture != nil:
+			return *a.StorageTexture == *b.StorageTexture
+		}
+		return false
+	}
+	return a.Binding == b.Binding && a.Visibility == b.Visibility && a.Count == b.Count && same()
+}
+
+func TestShaderReflection_BindGroupLayouts(t *testing.T) {
+	r, err := ReflectWGSL(ShaderSourceWGSL{Code: testReflectWGSL})
+	if err != nil {
+		t.Fatalf("ReflectWGSL() error = %v", err)
+	}
+
+	render := r.BindGroupLayouts("vs_main", "fs_main")
+	if len(render) != 2 || len(render[0].Entries) != 2 || len(render[1].Entries) != 2 {
+		t.Fatalf("render layouts = %+v", render)
+	}
+	if v := render[0].Entries[0].Visibility; v != ShaderStageVertex {
+		t.Errorf("camera visibility = %s", v)
+	}
+
+	all := r.BindGroupLayouts()
+	if len(all) != 3 || len(all[2].Entries) != 3 {
+		t.Fatalf("all layouts = %+v", all)
+	}
+	for i := range all {
+		if err := all[i].Validate(DefaultLimits(), Features(FeatureBindingArrays)); err != nil {
+			t.Errorf("group %d Validate() error = %v", i, err)
+		}
+	}
+
+	compute := r.BindGroupLayouts("cs_main")
+	if len(compute) != 3 || len(compute[0].Entries) != 0 || len(compute[1].Entries) != 0 {
+		t.Errorf("compute layouts = %+v", compute)
+	}
+}
+
+func TestReflectWGSL_SampleType(t *testing.T) {
+	src := `@group(0) @binding(0) var s: sampler;
+@group(0) @binding(1) var sampled: texture_2d<f32>;
+@group(0) @binding(2) var loaded: texture_2d<f32>;
+@group(0) @binding(3) var gathered: binding_array<texture_2d<f32>, 4>;
+@group(0) @binding(4) var ms: texture_multisampled_2d<f32>;
+@group(0) @binding(5) var param: texture_2d<f32>;
+
+fn sample_it(uv: vec2f) -> vec4f {
+    return textureSample(sampled, s, uv);
+}
+
+fn samp(tt: texture_2d<f32>, ss: sampler, uv: vec2f) -> vec4f {
+    return textureSample(tt, ss, uv);
+}
+
+fn outer(tex: texture_2d<f32>, uv: vec2f) -> vec4f {
+    return samp(tex, s, uv);
+}
+
+@fragment
+fn fs_main(@builtin(position) p: vec4f) -> @location(0) vec4f {
+    let a = textureLoad(loaded, vec2i(p.xy), 0) + textureLoad(ms, vec2i(p.xy), 0);
+    return a + sample_it(p.xy) + outer(param, p.xy) + textureGather(1, gathered[u32(p.x) % 4u], s, p.xy);
+}
+
+@compute @workgroup_size(1)
+fn cs_main() {
+    _ = textureLoad(sampled, vec2i(0), 0);
+    _ = textureLoad(param, vec2i(0), 0);
+}
+`
+	r, err := ReflectWGSL(ShaderSourceWGSL{Code: src})
+	if err != nil {
+		t.Fatalf("ReflectWGSL() error = %v", err)
+	}
+	want := map[string]TextureSampleType{
+		"sampled":  TextureSampleTypeFloat,
+		"loaded":   TextureSampleTypeUnfilterableFloat,
+		"gathered": TextureSampleTypeFloat,
+		"ms":       TextureSampleTypeUnfilterableFloat,
+		"param":    TextureSampleTypeFloat,
+	}
+	for _, b := range r.Bindings {
+		if w, ok := want[b.Name]; ok && (b.Entry.Texture == nil || b.Entry.Texture.SampleType != w) {
+			t.Errorf("%s = %+v, want sample type %s", b.Name, b.Entry.Texture, w)
+		}
+	}
+	if b := r.Bindings[5]; !slices.Equal(b.SampledBy, []string{"fs_main"}) {
+		t.Errorf("%s SampledBy = %v, want [fs_main]", b.Name, b.SampledBy)
+	}
+
+	compute := r.BindGroupLayouts("cs_main")
+	if len(compute) != 1 || len(compute[0].Entries) != 2 {
+		t.Fatalf("compute layouts = %+v", compute)
+	}
+	for _, e := range compute[0].Entries {
+		if e.Texture == nil || e.Texture.SampleType != TextureSampleTypeUnfilterableFloat {
+			t.Errorf("compute binding %d = %+v, want UnfilterableFloat", e.Binding, e.Texture)
+		}
+	}
+	if render := r.BindGroupLayouts("fs_main"); render[0].Entries[1].Texture.SampleType != TextureSampleTypeFloat {
+		t.Errorf("render binding 1 = %+v, want Float", render[0].Entries[1].Texture)
+	}
+	if r.Bindings[1].Entry.Texture.SampleType != TextureSampleTypeFloat {
+		t.Error("BindGroupLayouts modified Bindings")
+	}
+}
+
+func TestReflectWGSL_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		want      error
+		line, col int
+	}{
+		{"missing comma", "struct S {\n  a: f32\n  b: f32\n}", ErrWGSLParse, 3, 3},
+		{"unclosed body", "fn f() {\n  let x = 1;\n", ErrWGSLParse, 1, 8},
+		{"unterminated comment", "const a = 1;\n  /* open", ErrWGSLParse, 2, 3},
+		{"bad character", "const a = 1;\n$", ErrWGSLParse, 2, 1},
+		{"missing binding", "@group(0) var<uniform> u: f32;", ErrWGSLParse, 1, 11},
+		{"no workgroup size", "@compute fn main() {}", ErrWGSLParse, 1, 13},
+		{"unknown type", "@group(0) @binding(0) var<uniform> u: Missing;\n@vertex fn vs() {}", ErrWGSLParse, 1, 39},
+		{"io without location", "@fragment fn fs(x: f32) {}", ErrWGSLParse, 1, 20},
+		{"texture_external", "@group(0) @binding(0) var t: texture_external;", ErrWGSLUnsupported, 1, 30},
+		{"unsized binding array", "@group(0) @binding(0) var t: binding_array<sampler>;", ErrWGSLUnsupported, 1, 30},
+		{"comparison in attribute", "@compute @workgroup_size(select(1, 2, 3 > 2)) fn main() {}", ErrWGSLParse, 1, 26},
+		{"override expression", "override n: u32;\n@compute @workgroup_size(n * 2) fn main() {}", ErrWGSLUnsupported, 2, 26},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReflectWGSL(ShaderSourceWGSL{Code: tt.src})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ReflectWGSL() error = %v, want %v", err, tt.want)
+			}
+			var we *WGSLError
+			if !errors.As(err, &we) || we.Line != tt.line || we.Column != tt.col {
+				t.Errorf("ReflectWGSL() error = %v, want position %d:%d", err, tt.line, tt.col)
+			}
+		})
+	}
+}